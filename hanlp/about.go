@@ -0,0 +1,192 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// ServerInfo 服务端 /about 信息
+type ServerInfo struct {
+	Version   string   `json:"version"`
+	Languages []string `json:"languages"`
+	Tasks     []string `json:"tasks"`
+	Models    []string `json:"models"` // 文本分类模型
+	Styles    []Style  `json:"styles"` // 文本风格转换目标风格
+
+	Raw map[string]json.RawMessage `json:"-"` // 原始字段
+}
+
+// 不同版本服务端使用的字段名
+var (
+	aboutVersionKeys  = []string{"version", "hanlp_version"}
+	aboutLanguageKeys = []string{"languages", "language"}
+	aboutTaskKeys     = []string{"tasks", "task"}
+	aboutModelKeys    = []string{"models", "text_classification"}
+	aboutStyleKeys    = []string{"styles", "text_style_transfer"}
+)
+
+// UnmarshalServerInfo marshal /about
+func UnmarshalServerInfo(b []byte) (*ServerInfo, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		mylog.Error(err)
+		return nil, err
+	}
+
+	info := &ServerInfo{Raw: raw}
+	for _, k := range aboutVersionKeys {
+		if v, ok := raw[k]; ok {
+			info.Version = strings.Trim(string(v), `"`)
+			break
+		}
+	}
+	info.Languages = aboutNames(raw, aboutLanguageKeys)
+	info.Tasks = aboutNames(raw, aboutTaskKeys)
+	info.Models = aboutNames(raw, aboutModelKeys)
+	for _, s := range aboutNames(raw, aboutStyleKeys) {
+		info.Styles = append(info.Styles, Style(s))
+	}
+	return info, nil
+}
+
+// aboutNames 取第一个存在的字段, 支持字符串列表或以名称为键的对象
+func aboutNames(raw map[string]json.RawMessage, keys []string) []string {
+	for _, k := range keys {
+		v, ok := raw[k]
+		if !ok {
+			continue
+		}
+
+		var list []string
+		if err := json.Unmarshal(v, &list); err == nil {
+			return list
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(v, &obj); err == nil {
+			for name := range obj {
+				list = append(list, name)
+			}
+			sort.Strings(list)
+			return list
+		}
+	}
+	return nil
+}
+
+// SupportsLanguage 服务端未公布语言列表时视为支持
+func (s *ServerInfo) SupportsLanguage(language string) bool {
+	return len(s.Languages) == 0 || containsString(s.Languages, language)
+}
+
+// SupportsTask 支持按前缀匹配, 如 pos 与 pos/ctb, sdp/dm 与 sdp
+func (s *ServerInfo) SupportsTask(task string) bool {
+	if len(s.Tasks) == 0 {
+		return true
+	}
+	for _, t := range s.Tasks {
+		if t == task || strings.HasPrefix(t, task+"/") || strings.HasPrefix(task, t+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsModel 文本分类模型
+func (s *ServerInfo) SupportsModel(model string) bool {
+	return len(s.Models) == 0 || containsString(s.Models, model)
+}
+
+// SupportsStyle 文本风格转换目标风格
+func (s *ServerInfo) SupportsStyle(style Style) bool {
+	if len(s.Styles) == 0 {
+		return true
+	}
+	for _, v := range s.Styles {
+		if v == style {
+			return true
+		}
+	}
+	return false
+}
+
+// AboutObj server info (typed)
+func (h *hanlp) AboutObj(opts ...Option) (*ServerInfo, error) {
+	b, err := h.About(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalServerInfo([]byte(b))
+}
+
+// Capabilities 缓存的服务端信息, 按服务端地址缓存, 每个地址首次调用时请求 /about
+func (h *hanlp) Capabilities(opts ...Option) (*ServerInfo, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	h.mu.Lock()
+	info := h.info[options.URL]
+	h.mu.Unlock()
+	if info != nil {
+		return info, nil
+	}
+
+	// 请求时不持锁, 并发的首次调用各自请求, 以先写入的为准
+	info, err := h.AboutObj(opts...)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	if h.info == nil {
+		h.info = make(map[string]*ServerInfo)
+	}
+	if v, ok := h.info[options.URL]; ok {
+		info = v
+	} else {
+		h.info[options.URL] = info
+	}
+	h.mu.Unlock()
+	return info, nil
+}
+
+// ResetCapabilities 清空全部服务端的缓存, 服务端升级后调用
+func (h *hanlp) ResetCapabilities() {
+	h.mu.Lock()
+	h.info = nil
+	h.mu.Unlock()
+}
+
+// checkCapabilities 在本地拒绝服务端不支持的语言和任务
+func (h *hanlp) checkCapabilities(options Options) error {
+	if !options.CheckCapabilities {
+		return nil
+	}
+
+	info, err := h.Capabilities(withOptions(options))
+	if err != nil {
+		return err
+	}
+	if len(options.Language) > 0 && !info.SupportsLanguage(options.Language) {
+		return fmt.Errorf("language %q not supported by server, available: %v", options.Language, info.Languages)
+	}
+	for _, t := range options.Tasks {
+		if !info.SupportsTask(t) {
+			return fmt.Errorf("task %q not supported by server, available: %v", t, info.Tasks)
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package hanlp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalServerInfo(t *testing.T) {
+	info, err := UnmarshalServerInfo([]byte(`{
+		"hanlp_version": "2.1.0",
+		"languages": ["zh", "mul"],
+		"task": {"tok/fine": {}, "pos/ctb": {}, "sdp": {}},
+		"text_style_transfer": ["gov_doc"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "2.1.0" || !reflect.DeepEqual(info.Languages, []string{"zh", "mul"}) {
+		t.Errorf("info = %+v", info)
+	}
+	if !reflect.DeepEqual(info.Tasks, []string{"pos/ctb", "sdp", "tok/fine"}) || !reflect.DeepEqual(info.Styles, []Style{StyleGovDoc}) {
+		t.Errorf("Tasks = %v, Styles = %v", info.Tasks, info.Styles)
+	}
+	if len(info.Models) != 0 || !info.SupportsModel("any") {
+		t.Errorf("Models = %v", info.Models)
+	}
+
+	for task, want := range map[string]bool{"tok/fine": true, "pos": true, "sdp/dm": true, "tok/coarse": false, "ner": false} {
+		if got := info.SupportsTask(task); got != want {
+			t.Errorf("SupportsTask(%q) = %v, want %v", task, got, want)
+		}
+	}
+	if !info.SupportsLanguage("zh") || info.SupportsLanguage("ja") || !(&ServerInfo{}).SupportsLanguage("ja") {
+		t.Error("SupportsLanguage")
+	}
+
+	if _, err := UnmarshalServerInfo([]byte(`[`)); err == nil {
+		t.Error("malformed /about want error")
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		if r.Header.Get("Authorization") != "Basic key" {
+			t.Errorf("request %s, auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		if r.URL.Path == "/about" {
+			w.Write([]byte(`{"languages": ["zh"], "tasks": ["tok/fine", "pos/ctb"]}`))
+			return
+		}
+		w.Write([]byte(`{"tok/fine": [["a"]]}`))
+	}))
+	defer srv.Close()
+
+	// 调用方的 URL 和认证要同时用于 /about 和 /parse
+	h := HanLPClient(WithURL("http://127.0.0.1:0"))
+	opts := []Option{WithURL(srv.URL), WithAuth("key"), WithCapabilityCheck()}
+	if _, err := h.ParseObj([]string{"a"}, append(opts, WithTasks("tok/fine"))...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.ParseObj([]string{"a"}, append(opts, WithTasks("ner/msra"))...); err == nil {
+		t.Error("unsupported task want error")
+	}
+	if _, err := h.ParseObj([]string{"a"}, append(opts, WithLanguage("ja"))...); err == nil {
+		t.Error("unsupported language want error")
+	}
+	if hits["/about"] != 1 || hits["/parse"] != 1 {
+		t.Errorf("requests = %v, want /about 1 (cached), /parse 1", hits)
+	}
+
+	options := h.opts
+	if err := h.checkCapabilities(options); err != nil {
+		t.Errorf("check disabled: %v", err)
+	}
+}
+
+func TestCapabilitiesPerURL(t *testing.T) {
+	newServer := func(tasks string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"tasks": ` + tasks + `}`))
+		}))
+	}
+	a, b := newServer(`["tok/fine"]`), newServer(`["ner/msra"]`)
+	defer a.Close()
+	defer b.Close()
+
+	h := HanLPClient(WithURL(a.URL))
+	infoA, err := h.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	infoB, err := h.Capabilities(WithURL(b.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !infoA.SupportsTask("tok/fine") || infoA.SupportsTask("ner/msra") || !infoB.SupportsTask("ner/msra") {
+		t.Errorf("Capabilities = %v, %v", infoA.Tasks, infoB.Tasks)
+	}
+}
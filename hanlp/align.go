@@ -0,0 +1,265 @@
+package hanlp
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Aligner 将词对齐到原文. 比较时忽略空白, 全角字符按半角、字母按小写处理;
+// 服务端改写过、在原文中找不到的词分得前后两个已对齐词之间的区间.
+// 跳过一段原文的匹配只有在不减少后续词的匹配数时才被接受, 以免改写后的词误配到后文
+type Aligner struct {
+	text  string
+	src   []rune
+	bytes []int  // src[i] 的字节偏移, 末尾为 len(text)
+	norm  []rune // 归一化并去掉空白后的字符
+	pos   []int  // norm[k] 在 src 中的下标
+}
+
+// NewAligner 以 text 为原文构造对齐器
+func NewAligner(text string) *Aligner {
+	a := &Aligner{text: text}
+	for b, r := range text {
+		if !unicode.IsSpace(r) {
+			a.norm = append(a.norm, normRune(r))
+			a.pos = append(a.pos, len(a.src))
+		}
+		a.src = append(a.src, r)
+		a.bytes = append(a.bytes, b)
+	}
+	a.bytes = append(a.bytes, len(text))
+	return a
+}
+
+// Text 原文
+func (a *Aligner) Text() string {
+	return a.text
+}
+
+// Align 按顺序对齐 tokens, 返回每个词的字符和字节区间, Text 为词本身
+func (a *Aligner) Align(tokens []string) []TokenOffset {
+	re := make([]TokenOffset, len(tokens))
+	found := make([]bool, len(tokens))
+	cur := 0
+	for i, tok := range tokens {
+		sub := normString(tok)
+		k := indexRunes(a.norm, sub, cur)
+		if k < 0 {
+			continue
+		}
+		if k > cur { // 跳过了一段原文, 可能是改写后的词误配到后文
+			next := tokens[i+1 : minInt(i+1+alignLookahead, len(tokens))]
+			if a.matches(next, k+len(sub)) < a.matches(next, cur) {
+				continue
+			}
+		}
+		re[i] = a.offset(tok, a.pos[k], a.pos[k+len(sub)-1]+1)
+		found[i] = true
+		cur = k + len(sub)
+	}
+
+	for i := 0; i < len(tokens); {
+		if found[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(tokens) && !found[j] {
+			j++
+		}
+		lo, hi := 0, len(a.src)
+		if i > 0 {
+			lo = re[i-1].End
+		}
+		if j < len(tokens) {
+			hi = re[j].Begin
+		}
+		for k := i; k < j; k++ {
+			lo = a.skipSpace(lo, hi)
+			end := lo + len(normString(tokens[k]))
+			if end > hi || (k == j-1 && j < len(tokens)) { // 夹在两个已对齐词之间时, 最后一个词占满剩余区间
+				end = hi
+			}
+			for end > lo && unicode.IsSpace(a.src[end-1]) {
+				end--
+			}
+			re[k] = a.offset(tokens[k], lo, end)
+			lo = end
+		}
+		i = j
+	}
+	return re
+}
+
+// alignLookahead 判断是否接受跳跃匹配时向后检查的词数
+const alignLookahead = 8
+
+// matches 从 cur 开始按顺序贪心匹配 tokens, 返回匹配上的词数
+func (a *Aligner) matches(tokens []string, cur int) int {
+	n := 0
+	for _, tok := range tokens {
+		sub := normString(tok)
+		if k := indexRunes(a.norm, sub, cur); k >= 0 {
+			cur = k + len(sub)
+			n++
+		}
+	}
+	return n
+}
+
+// Span 词区间 [begin, end) 在原文中的字符区间, Text 为原文片段
+func (a *Aligner) Span(offsets []TokenOffset, begin, end int) (TokenOffset, bool) {
+	if begin < 0 || begin >= end || end > len(offsets) {
+		return TokenOffset{}, false
+	}
+	b, e := offsets[begin].Begin, offsets[end-1].End
+	return a.offset(string(a.src[b:e]), b, e), true
+}
+
+// alignDocument 文档中各句的词依次对齐到整段原文, 偏移相对整段
+func (a *Aligner) alignDocument(tokens [][]string) [][]TokenOffset {
+	var flat []string
+	for _, v := range tokens {
+		flat = append(flat, v...)
+	}
+	offsets := a.Align(flat)
+
+	re := make([][]TokenOffset, len(tokens))
+	i := 0
+	for k, v := range tokens {
+		re[k] = offsets[i : i+len(v)]
+		i += len(v)
+	}
+	return re
+}
+
+func (a *Aligner) offset(text string, begin, end int) TokenOffset {
+	return TokenOffset{Text: text, Begin: begin, End: end, ByteBegin: a.bytes[begin], ByteEnd: a.bytes[end]}
+}
+
+func (a *Aligner) skipSpace(i, end int) int {
+	for i < end && unicode.IsSpace(a.src[i]) {
+		i++
+	}
+	return i
+}
+
+// normRune 全角转半角, 字母转小写
+func normRune(r rune) rune {
+	if r >= 0xFF01 && r <= 0xFF5E {
+		r -= 0xFEE0
+	}
+	return unicode.ToLower(r)
+}
+
+// normString 归一化并去掉空白
+func normString(s string) []rune {
+	var re []rune
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			re = append(re, normRune(r))
+		}
+	}
+	return re
+}
+
+// indexRunes sub 在 src[from:] 中首次出现的位置
+func indexRunes(src, sub []rune, from int) int {
+	if len(sub) == 0 {
+		return -1
+	}
+	for i := from; i+len(sub) <= len(src); i++ {
+		match := true
+		for j := range sub {
+			if src[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// Alignment HanResp 的分词结果与原文的对齐
+type Alignment struct {
+	Tokens   [][]TokenOffset // 每句每词
+	aligners []*Aligner      // 每句的原文, 文档模式下各句共用整段原文
+}
+
+/*
+Align 将每句的词对齐到原文. text 为 Parse 的输入时每句一个元素, 偏移相对所在句子;
+为 ParseDocument 的整段文本时只传一个元素, 偏移相对整段. 不传时使用 ParseDocumentObj 还原的 Sentences
+*/
+func (r *HanResp) Align(text ...string) (*Alignment, error) {
+	toks := r.Tokens()
+	if len(text) == 0 {
+		if len(r.Sentences) != len(toks) {
+			return nil, fmt.Errorf("align: no source text")
+		}
+		text = r.Sentences
+	}
+
+	al := &Alignment{}
+	switch {
+	case len(text) == 1:
+		a := NewAligner(text[0])
+		al.Tokens = a.alignDocument(toks)
+		for range toks {
+			al.aligners = append(al.aligners, a)
+		}
+	case len(text) == len(toks):
+		for i, v := range toks {
+			a := NewAligner(text[i])
+			al.Tokens = append(al.Tokens, a.Align(v))
+			al.aligners = append(al.aligners, a)
+		}
+	default:
+		return nil, fmt.Errorf("align: %d texts but %d tokenized sentences", len(text), len(toks))
+	}
+	return al, nil
+}
+
+// Span 第 sent 句词区间 [begin, end) 在原文中的字符区间
+func (al *Alignment) Span(sent, begin, end int) (TokenOffset, bool) {
+	if sent < 0 || sent >= len(al.Tokens) {
+		return TokenOffset{}, false
+	}
+	return al.aligners[sent].Span(al.Tokens[sent], begin, end)
+}
+
+// Entities 实体(如 HanResp.NerMsra)在原文中的字符区间, 与输入一一对应, 区间非法时 Begin, End 为 -1
+func (al *Alignment) Entities(ner [][]NerTuple) [][]TokenOffset {
+	re := make([][]TokenOffset, len(ner))
+	for i, sent := range ner {
+		re[i] = make([]TokenOffset, len(sent))
+		for j, e := range sent {
+			re[i][j] = al.span(i, e.Begin, e.End)
+		}
+	}
+	return re
+}
+
+// Args 谓词和论元(HanResp.Srl)在原文中的字符区间, 与输入一一对应, 区间非法时 Begin, End 为 -1
+func (al *Alignment) Args(srl [][][]SrlTuple) [][][]TokenOffset {
+	re := make([][][]TokenOffset, len(srl))
+	for i, sent := range srl {
+		re[i] = make([][]TokenOffset, len(sent))
+		for j, frame := range sent {
+			re[i][j] = make([]TokenOffset, len(frame))
+			for k, a := range frame {
+				re[i][j][k] = al.span(i, a.Begin, a.End)
+			}
+		}
+	}
+	return re
+}
+
+func (al *Alignment) span(sent, begin, end int) TokenOffset {
+	if s, ok := al.Span(sent, begin, end); ok {
+		return s
+	}
+	return TokenOffset{Begin: -1, End: -1, ByteBegin: -1, ByteEnd: -1}
+}
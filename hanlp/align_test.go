@@ -0,0 +1,67 @@
+package hanlp
+
+import (
+	"testing"
+)
+
+func TestAligner(t *testing.T) {
+	for _, c := range []struct {
+		text   string
+		tokens []string
+		want   [][2]int
+	}{
+		{"商品和服务。", []string{"商品", "和", "服务", "。"}, [][2]int{{0, 2}, {2, 3}, {3, 5}, {5, 6}}},
+		{"New  York is big", []string{"New York", "is", "big"}, [][2]int{{0, 9}, {10, 12}, {13, 16}}},
+		{"ＨａｎＬＰ２．０", []string{"hanlp", "2.0"}, [][2]int{{0, 5}, {5, 8}}},
+		{"他说：“好”", []string{"他", "说", ":", "\"", "好", "\""}, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}}},
+		// 改写后的 "零" 在后文出现, 不能误配
+		{"二〇二一年，零点", []string{"二", "零", "二", "一", "年", "，", "零点"}, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}, {6, 8}}},
+		{"二〇二一年", []string{"二", "零", "二一年"}, [][2]int{{0, 1}, {1, 2}, {2, 5}}},
+	} {
+		got := NewAligner(c.text).Align(c.tokens)
+		for i, o := range got {
+			if o.Begin != c.want[i][0] || o.End != c.want[i][1] {
+				t.Errorf("Align(%q)[%d] = [%d, %d), want %v", c.text, i, o.Begin, o.End, c.want[i])
+			}
+		}
+	}
+
+	a := NewAligner("a 北京")
+	off := a.Align([]string{"a", "北京"})
+	if off[1].ByteBegin != 2 || off[1].ByteEnd != 8 {
+		t.Errorf("byte offsets = [%d, %d)", off[1].ByteBegin, off[1].ByteEnd)
+	}
+	if s, ok := a.Span(off, 0, 2); !ok || s.Text != "a 北京" {
+		t.Errorf("Span = %+v", s)
+	}
+}
+
+func TestAlignment(t *testing.T) {
+	resp := newSampleResp(t)
+	resp.TokFine = append(resp.TokFine, []string{"她", "好"})
+
+	al, err := resp.Align("晓美焰来到北京 立方庭。她很好")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := al.Tokens[1][0]; got.Begin != 12 || got.End != 13 {
+		t.Errorf("Tokens[1][0] = %+v", got)
+	}
+	ents := al.Entities(resp.NerMsra)
+	if e := ents[0][1]; e.Text != "北京 立方庭" || e.Begin != 5 || e.End != 11 {
+		t.Errorf("Entities = %+v", ents)
+	}
+	args := al.Args([][][]SrlTuple{{{{"x", "ARG0", 3, 9}}}})
+	if args[0][0][0].Begin != -1 {
+		t.Errorf("out of range arg = %+v", args[0][0][0])
+	}
+
+	// 服务端把 "很" 改写为 "挺", 该词分得前后两个已对齐词之间的区间
+	off := NewAligner("她很好").Align([]string{"她", "挺", "好"})
+	if off[1].Begin != 1 || off[1].End != 2 || off[2].Begin != 2 {
+		t.Errorf("rewritten token = %+v", off)
+	}
+	if _, err := resp.Align("a", "b", "c"); err == nil {
+		t.Error("mismatched text want error")
+	}
+}
@@ -0,0 +1,514 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// https://github.com/cfmrp/mtool  Meaning Representation Parsing (MRP) 格式
+
+// AMRAnchor 在 Input 中的字符(rune)区间, 左闭右开
+type AMRAnchor struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// AMRNode 概念节点
+type AMRNode struct {
+	ID         int         `json:"id"`
+	Label      string      `json:"label"`
+	Properties []string    `json:"properties,omitempty"`
+	Values     []string    `json:"values,omitempty"`
+	Anchors    []AMRAnchor `json:"anchors,omitempty"`
+}
+
+// AMREdge 关系
+type AMREdge struct {
+	Source int    `json:"source"`
+	Target int    `json:"target"`
+	Label  string `json:"label"`
+}
+
+// AMRGraph 抽象意义表示
+type AMRGraph struct {
+	ID        string    `json:"id"`
+	Input     string    `json:"input"` // 以空格分隔的分词结果
+	Tops      []int     `json:"tops"`
+	Nodes     []AMRNode `json:"nodes"`
+	Edges     []AMREdge `json:"edges"`
+	Framework string    `json:"framework,omitempty"`
+}
+
+/*
+抽象意义表示
+Abstract Meaning Representation (AMR) captures “who is doing what to whom” in a sentence. Each sentence is
+
+	represented as a rooted, directed, acyclic graph consisting of nodes (concepts) and edges (relations).
+
+	Args:
+	    text: A document (str), or a list of sentences (List[str]).
+	    tokens: A list of sentences where each sentence is a list of tokens.
+	    language: The language of input text or tokens. ``None`` to use the default language on server.
+
+	Returns:
+	    Graphs in meaning representation format.
+
+	Examples::
+
+	    HanLP.abstract_meaning_representation('男孩希望女孩相信他。')
+	    HanLP.abstract_meaning_representation('The boy wants the girl to believe him.',
+	                                          language='en')
+*/
+func (h *hanlp) AbstractMeaningRepresentation(text []string, opts ...Option) ([]AMRGraph, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	req := &HanReq{
+		Text:     text,
+		Language: options.Language, // (zh,mnt)
+	}
+	return h.postAMR(req, options)
+}
+
+// AbstractMeaningRepresentationTokens 抽象意义表示(已分词输入)
+func (h *hanlp) AbstractMeaningRepresentationTokens(tokens [][]string, opts ...Option) ([]AMRGraph, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	req := &HanReq{
+		Tokens:   tokens,
+		Language: options.Language, // (zh,mnt)
+	}
+	return h.postAMR(req, options)
+}
+
+func (h *hanlp) postAMR(req *HanReq, options Options) ([]AMRGraph, error) {
+	b, err := h.post(options, "/abstract_meaning_representation", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var re []AMRGraph
+	if err = json.Unmarshal([]byte(b), &re); err != nil {
+		mylog.Error(err)
+		return nil, err
+	}
+	return re, nil
+}
+
+// Node 按 id 查找节点
+func (g *AMRGraph) Node(id int) (*AMRNode, bool) {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Root 第一个顶点
+func (g *AMRGraph) Root() (*AMRNode, bool) {
+	if len(g.Tops) == 0 {
+		return nil, false
+	}
+	return g.Node(g.Tops[0])
+}
+
+// Children 节点的出边
+func (g *AMRGraph) Children(id int) []AMREdge {
+	var re []AMREdge
+	for _, e := range g.Edges {
+		if e.Source == id {
+			re = append(re, e)
+		}
+	}
+	return re
+}
+
+// Parents 节点的入边
+func (g *AMRGraph) Parents(id int) []AMREdge {
+	var re []AMREdge
+	for _, e := range g.Edges {
+		if e.Target == id {
+			re = append(re, e)
+		}
+	}
+	return re
+}
+
+// Walk 从各顶点深度优先遍历, 每个节点只访问一次. via 为到达该节点的边(顶点为 nil), fn 返回 false 时不再展开该节点
+func (g *AMRGraph) Walk(fn func(n *AMRNode, depth int, via *AMREdge) bool) {
+	visited := make(map[int]bool)
+	var walk func(id, depth int, via *AMREdge)
+	walk = func(id, depth int, via *AMREdge) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		n, ok := g.Node(id)
+		if !ok || !fn(n, depth, via) {
+			return
+		}
+		for _, e := range g.Children(id) {
+			e := e
+			walk(e.Target, depth+1, &e)
+		}
+	}
+	for _, id := range g.roots() {
+		walk(id, 0, nil)
+	}
+}
+
+// roots 顶点, 以及从顶点不可达的无入边节点
+func (g *AMRGraph) roots() []int {
+	re := append([]int(nil), g.Tops...)
+	for _, n := range g.Nodes {
+		if len(g.Parents(n.ID)) == 0 && !containsInt(re, n.ID) {
+			re = append(re, n.ID)
+		}
+	}
+	return re
+}
+
+// TokenAlignments 节点对齐到的词下标(Input 以空格分隔)
+func (g *AMRGraph) TokenAlignments(id int) []int {
+	n, ok := g.Node(id)
+	if !ok {
+		return nil
+	}
+
+	var re []int
+	for i, span := range g.tokenSpans() {
+		for _, a := range n.Anchors {
+			if a.From < span.To && span.From < a.To {
+				re = append(re, i)
+				break
+			}
+		}
+	}
+	return re
+}
+
+// AnchorText 节点锚定的原文片段
+func (g *AMRGraph) AnchorText(id int) []string {
+	n, ok := g.Node(id)
+	if !ok {
+		return nil
+	}
+
+	input := []rune(g.Input)
+	var re []string
+	for _, a := range n.Anchors {
+		if a.From >= 0 && a.From <= a.To && a.To <= len(input) {
+			re = append(re, string(input[a.From:a.To]))
+		}
+	}
+	return re
+}
+
+func (g *AMRGraph) tokenSpans() []AMRAnchor {
+	var re []AMRAnchor
+	begin := -1
+	for i, r := range []rune(g.Input + " ") {
+		if r == ' ' {
+			if begin >= 0 {
+				re = append(re, AMRAnchor{From: begin, To: i})
+				begin = -1
+			}
+		} else if begin < 0 {
+			begin = i
+		}
+	}
+	return re
+}
+
+// PENMAN 序列化为 PENMAN 格式, 变量名为 z<id>. 多个顶点时每棵树占一段, 指向不存在节点的边只写出变量名
+func (g *AMRGraph) PENMAN() string {
+	var sb strings.Builder
+	visited := make(map[int]bool)
+	var write func(id, depth int)
+	write = func(id, depth int) {
+		visited[id] = true
+		n, ok := g.Node(id)
+		if !ok {
+			fmt.Fprintf(&sb, "z%d", id)
+			return
+		}
+		fmt.Fprintf(&sb, "(z%d / %s", id, penmanAtom(n.Label))
+		indent := strings.Repeat("    ", depth+1)
+		for i, p := range n.Properties {
+			v := ""
+			if i < len(n.Values) {
+				v = n.Values[i]
+			}
+			fmt.Fprintf(&sb, "\n%s:%s %s", indent, p, penmanValue(v))
+		}
+		for _, e := range g.Children(id) {
+			fmt.Fprintf(&sb, "\n%s:%s ", indent, e.Label)
+			if visited[e.Target] {
+				fmt.Fprintf(&sb, "z%d", e.Target)
+			} else {
+				write(e.Target, depth+1)
+			}
+		}
+		sb.WriteString(")")
+	}
+
+	for _, id := range g.roots() {
+		if visited[id] {
+			continue
+		}
+		if _, ok := g.Node(id); !ok {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		write(id, 0)
+	}
+	return sb.String()
+}
+
+func penmanAtom(s string) string {
+	if s == "" || strings.ContainsAny(s, " ()\":/\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// penmanValue 属性值, 形如变量名 z<n> 时加引号以免解析为引用
+func penmanValue(s string) string {
+	if len(s) > 1 && s[0] == 'z' && strings.Trim(s[1:], "0123456789") == "" {
+		return strconv.Quote(s)
+	}
+	return penmanAtom(s)
+}
+
+// penmanNonInverted 以 -of 结尾但不是逆角色的角色
+var penmanNonInverted = map[string]bool{"consist-of": true, "prep-out-of": true, "prep-on-behalf-of": true}
+
+// penmanRole 逆角色 :ARG0-of 返回 ARG0, true
+func penmanRole(role string) (string, bool) {
+	if strings.HasSuffix(role, "-of") && !penmanNonInverted[role] {
+		return strings.TrimSuffix(role, "-of"), true
+	}
+	return role, false
+}
+
+// ParsePENMAN 解析 PENMAN 格式, 支持连续多棵树. 变量名形如 z<n> 时使用 n 作为节点 id.
+// 逆角色(如 :ARG0-of)转换为反方向的边, 变量重复声明时返回错误
+func ParsePENMAN(s string) (*AMRGraph, error) {
+	p := &penmanParser{src: []rune(s), vars: make(map[string]int)}
+	g := &AMRGraph{Framework: "amr"}
+	type pending struct {
+		source int
+		label  string
+		value  string
+		quoted bool // 带引号的常量, 不作为变量引用
+	}
+	var refs []pending
+
+	var node func() (int, error)
+	node = func() (int, error) {
+		if err := p.expect("("); err != nil {
+			return 0, err
+		}
+		v, err := p.symbol()
+		if err != nil {
+			return 0, err
+		}
+		if err = p.expect("/"); err != nil {
+			return 0, err
+		}
+		label, err := p.symbol()
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := p.vars[v]; ok {
+			return 0, p.errorf("variable %q redeclared", v)
+		}
+		id := p.id(v)
+		g.Nodes = append(g.Nodes, AMRNode{ID: id, Label: label})
+		for {
+			switch p.peek() {
+			case ')':
+				p.pos++
+				return id, nil
+			case ':':
+				role, err := p.symbol()
+				if err != nil {
+					return 0, err
+				}
+				role = strings.TrimPrefix(role, ":")
+				if p.peek() == '(' {
+					target, err := node()
+					if err != nil {
+						return 0, err
+					}
+					g.Edges = append(g.Edges, penmanEdge(id, target, role))
+				} else {
+					quoted := p.peek() == '"'
+					value, err := p.symbol()
+					if err != nil {
+						return 0, err
+					}
+					refs = append(refs, pending{source: id, label: role, value: value, quoted: quoted})
+				}
+			default:
+				return 0, p.errorf("want role or ')'")
+			}
+		}
+	}
+
+	for p.peek() != 0 {
+		top, err := node()
+		if err != nil {
+			return nil, err
+		}
+		g.Tops = append(g.Tops, top)
+	}
+	if len(g.Nodes) == 0 {
+		return nil, fmt.Errorf("penman: empty graph")
+	}
+
+	// 变量引用为边, 其余为属性
+	for _, r := range refs {
+		if id, ok := p.vars[r.value]; ok && !r.quoted {
+			g.Edges = append(g.Edges, penmanEdge(r.source, id, r.label))
+			continue
+		}
+		n, _ := g.Node(r.source)
+		n.Properties = append(n.Properties, r.label)
+		n.Values = append(n.Values, r.value)
+	}
+	sort.SliceStable(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	return g, nil
+}
+
+// penmanEdge 逆角色交换方向
+func penmanEdge(source, target int, role string) AMREdge {
+	if label, inverted := penmanRole(role); inverted {
+		return AMREdge{Source: target, Target: source, Label: label}
+	}
+	return AMREdge{Source: source, Target: target, Label: role}
+}
+
+type penmanParser struct {
+	src  []rune
+	pos  int
+	vars map[string]int
+	next int
+}
+
+func (p *penmanParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *penmanParser) peek() rune {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *penmanParser) expect(s string) error {
+	if p.peek() != []rune(s)[0] {
+		return p.errorf("want %q", s)
+	}
+	p.pos++
+	return nil
+}
+
+// symbol 读取变量/概念/角色/常量, 支持双引号字符串
+func (p *penmanParser) symbol() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return "", p.errorf("unexpected end")
+	}
+	if p.src[p.pos] == '"' {
+		end := p.pos + 1
+		for end < len(p.src) && p.src[end] != '"' {
+			if p.src[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(p.src) {
+			return "", p.errorf("unterminated string")
+		}
+		s, err := strconv.Unquote(string(p.src[p.pos : end+1]))
+		if err != nil {
+			return "", p.errorf("%v", err)
+		}
+		p.pos = end + 1
+		return s, nil
+	}
+
+	begin := p.pos
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '/' || (r == ':' && p.pos > begin) {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == begin {
+		return "", p.errorf("want symbol")
+	}
+	return string(p.src[begin:p.pos]), nil
+}
+
+func (p *penmanParser) id(v string) int {
+	if id, ok := p.vars[v]; ok {
+		return id
+	}
+	id := -1
+	if strings.HasPrefix(v, "z") {
+		if n, err := strconv.Atoi(v[1:]); err == nil && n >= 0 && !p.used(n) {
+			id = n
+		}
+	}
+	if id < 0 {
+		for p.used(p.next) {
+			p.next++
+		}
+		id = p.next
+	}
+	p.vars[v] = id
+	return id
+}
+
+func (p *penmanParser) used(id int) bool {
+	for _, v := range p.vars {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *penmanParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("penman: at %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// amrSample 男孩希望女孩相信他。boy 被 want-01 和 believe-01 共同指向(reentrancy)
+const amrSample = `{
+  "id": "0", "input": "男孩 希望 女孩 相信 他 。", "framework": "amr", "tops": [0],
+  "nodes": [
+    {"id": 0, "label": "希望-01", "anchors": [{"from": 3, "to": 5}]},
+    {"id": 1, "label": "男孩", "anchors": [{"from": 0, "to": 2}]},
+    {"id": 2, "label": "相信-01", "anchors": [{"from": 9, "to": 11}]},
+    {"id": 3, "label": "女孩", "properties": ["name"], "values": ["小 红"]}
+  ],
+  "edges": [
+    {"source": 0, "target": 1, "label": "arg0"},
+    {"source": 0, "target": 2, "label": "arg1"},
+    {"source": 2, "target": 3, "label": "arg0"},
+    {"source": 2, "target": 1, "label": "arg1"}
+  ]
+}`
+
+func TestAMRPENMAN(t *testing.T) {
+	var g AMRGraph
+	if err := json.Unmarshal([]byte(amrSample), &g); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `(z0 / 希望-01
+    :arg0 (z1 / 男孩)
+    :arg1 (z2 / 相信-01
+        :arg0 (z3 / 女孩
+            :name "小 红")
+        :arg1 z1))`
+	if got := g.PENMAN(); got != want {
+		t.Fatalf("PENMAN =\n%s\nwant\n%s", got, want)
+	}
+
+	parsed, err := ParsePENMAN(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Tops, g.Tops) || len(parsed.Nodes) != 4 || len(parsed.Edges) != 4 {
+		t.Errorf("ParsePENMAN = %+v", parsed)
+	}
+	if got := parsed.PENMAN(); got != want {
+		t.Errorf("round trip =\n%s", got)
+	}
+
+	// 男孩 有两个父节点
+	if ps := parsed.Parents(1); len(ps) != 2 {
+		t.Errorf("Parents(1) = %v", ps)
+	}
+	if got := g.AnchorText(1); !reflect.DeepEqual(got, []string{"男孩"}) || !reflect.DeepEqual(g.TokenAlignments(0), []int{1}) {
+		t.Errorf("AnchorText = %v, TokenAlignments = %v", got, g.TokenAlignments(0))
+	}
+	var visits []int
+	parsed.Walk(func(n *AMRNode, depth int, via *AMREdge) bool {
+		visits = append(visits, n.ID)
+		return true
+	})
+	if !reflect.DeepEqual(visits, []int{0, 1, 2, 3}) {
+		t.Errorf("Walk = %v", visits)
+	}
+}
+
+func TestParsePENMANInverse(t *testing.T) {
+	g, err := ParsePENMAN(`(b / boy :ARG0-of (w / want-01 :ARG1 (g / girl :consist-of b)))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, w, gl := g.Nodes[0].ID, g.Nodes[1].ID, g.Nodes[2].ID
+	want := []AMREdge{{Source: w, Target: gl, Label: "ARG1"}, {Source: w, Target: b, Label: "ARG0"}, {Source: gl, Target: b, Label: "consist-of"}}
+	if !reflect.DeepEqual(g.Edges, want) {
+		t.Errorf("Edges = %+v, want %+v", g.Edges, want)
+	}
+	if ch := g.Children(w); len(ch) != 2 || len(g.Children(b)) != 0 {
+		t.Errorf("Children(want) = %v", ch)
+	}
+}
+
+func TestParsePENMANQuoted(t *testing.T) {
+	g, err := ParsePENMAN(`(a / boy :name "a" :mod z0)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := g.Nodes[0]
+	if len(g.Edges) != 0 || !reflect.DeepEqual(n.Properties, []string{"name", "mod"}) || !reflect.DeepEqual(n.Values, []string{"a", "z0"}) {
+		t.Fatalf("Nodes = %+v, Edges = %+v", g.Nodes, g.Edges)
+	}
+
+	// 形如变量名的属性值序列化时加引号
+	out := g.PENMAN()
+	if !strings.Contains(out, `:mod "z0"`) {
+		t.Errorf("PENMAN = %s", out)
+	}
+	back, err := ParsePENMAN(out)
+	if err != nil || len(back.Edges) != 0 || !reflect.DeepEqual(back.Nodes[0].Values, n.Values) {
+		t.Errorf("round trip = %+v, %v", back, err)
+	}
+}
+
+func TestAMRDanglingEdge(t *testing.T) {
+	g := AMRGraph{Tops: []int{0}, Nodes: []AMRNode{{ID: 0, Label: "go-02"}}, Edges: []AMREdge{{Source: 0, Target: 9, Label: "ARG0"}}}
+	if got := g.PENMAN(); !strings.Contains(got, ":ARG0 z9") {
+		t.Errorf("PENMAN = %s", got)
+	}
+	if _, ok := g.Node(9); ok || g.AnchorText(9) != nil {
+		t.Error("Node(9) want missing")
+	}
+}
+
+func TestParsePENMANMalformed(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"(a / boy",
+		"(a boy)",
+		"(a / boy :ARG0)",
+		`(a / boy :name "x)`,
+		"(a / boy :ARG0 (a / girl))",
+		"(a / boy) (a / girl)",
+	} {
+		if g, err := ParsePENMAN(s); err == nil {
+			t.Errorf("ParsePENMAN(%q) = %+v, want error", s, g)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package hanlp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// https://universaldependencies.org/format.html
+
+// conlluXposTasks 可写入 XPOS 列的词性任务键, 按优先级
+var conlluXposTasks = []string{"pos/ctb", "pos/pku", "pos/863", "xpos"}
+
+// conlluXposColumn xpos 对应的字段, 不是词性任务时返回 nil
+func (r *HanResp) conlluXposColumn(xpos string) *[][]string {
+	for _, k := range conlluXposTasks {
+		if k == xpos {
+			return r.column(k)
+		}
+	}
+	return nil
+}
+
+// WriteCoNLLU 以 CoNLL-U 格式输出. xpos 为写入 XPOS 列的词性任务键(pos/ctb, pos/pku, pos/863, xpos),
+// 为空时取第一个有结果的. UPOS 列为多语种的 pos, DEPS 列为语义依存 sdp
+func (r *HanResp) WriteCoNLLU(w io.Writer, xpos string) error {
+	if err := r.checkLengths(); err != nil {
+		return err
+	}
+	if xpos == "" {
+		for _, k := range conlluXposTasks {
+			if len(*r.column(k)) > 0 {
+				xpos = k
+				break
+			}
+		}
+	}
+	var xposCol [][]string
+	if xpos != "" {
+		p := r.conlluXposColumn(xpos)
+		if p == nil {
+			return fmt.Errorf("conllu: unknown pos task %q", xpos)
+		}
+		xposCol = *p
+	}
+
+	bw := bufio.NewWriter(w)
+	toks := r.Tokens()
+	for i, sent := range toks {
+		text := joinTokens(sent)
+		if len(r.Sentences) == len(toks) {
+			text = r.Sentences[i]
+		}
+		fmt.Fprintf(bw, "# sent_id = %d\n# text = %s\n", i+1, conlluSpace.Replace(text))
+
+		for j, form := range sent {
+			head, rel := "_", "_"
+			if i < len(r.Dep) && r.Dep[i][j].Head >= 0 { // 宽松解码的占位写作 _
+				head, rel = strconv.Itoa(r.Dep[i][j].Head), r.Dep[i][j].Relation
+			}
+			deps := "_"
+			if i < len(r.Sdp) && len(r.Sdp[i][j]) > 0 {
+				var arcs []string
+				for _, a := range r.Sdp[i][j] {
+					if a.Head >= 0 {
+						arcs = append(arcs, fmt.Sprintf("%d:%s", a.Head, a.Relation))
+					}
+				}
+				if len(arcs) > 0 {
+					deps = strings.Join(arcs, "|")
+				}
+			}
+			fmt.Fprintf(bw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t_\n", j+1, conlluField(form),
+				conlluCell(r.Lem, i, j), conlluCell(r.Pos, i, j), conlluCell(xposCol, i, j),
+				conlluCell(r.Fea, i, j), head, conlluField(rel), deps)
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// ReadCoNLLU 读取 CoNLL-U 到 HanResp: FORM 为 TokFine, XPOS 写入 xpos 指定的词性任务(为空时写入 Xpos),
+// 其余列依次为 Lem, Pos(UPOS), Fea, Dep, Sdp(DEPS). 全为 "_" 的列不填充, 多词符号与空节点行被忽略
+func ReadCoNLLU(rd io.Reader, xpos string) (*HanResp, error) {
+	if xpos == "" {
+		xpos = "xpos"
+	}
+	resp := &HanResp{}
+	xposCol := resp.conlluXposColumn(xpos)
+	if xposCol == nil {
+		return nil, fmt.Errorf("conllu: unknown pos task %q", xpos)
+	}
+
+	var (
+		forms, lems, upos, xposs, feas [][]string
+		deps                           [][]DepTuple
+		sdps                           [][][]DepTuple
+		texts                          []string
+		seen                           = make(map[string]bool) // 出现过非 "_" 值的列
+	)
+	var (
+		form, lem, up, xp, fea []string
+		dep                    []DepTuple
+		sdp                    [][]DepTuple
+		text                   string
+		hasText                bool
+	)
+	flush := func() {
+		if len(form) == 0 {
+			return
+		}
+		forms, lems, upos, xposs, feas = append(forms, form), append(lems, lem), append(upos, up), append(xposs, xp), append(feas, fea)
+		deps, sdps = append(deps, dep), append(sdps, sdp)
+		if hasText {
+			texts = append(texts, text)
+		}
+		form, lem, up, xp, fea, dep, sdp, text, hasText = nil, nil, nil, nil, nil, nil, nil, "", false
+	}
+
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(s) == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(s, "#") {
+			if strings.HasPrefix(s, "# text =") {
+				text, hasText = strings.TrimSpace(strings.TrimPrefix(s, "# text =")), true
+			}
+			continue
+		}
+
+		cols := strings.Split(s, "\t")
+		if len(cols) != 10 {
+			return nil, fmt.Errorf("conllu: line %d: want 10 columns, got %d", line, len(cols))
+		}
+		if strings.ContainsAny(cols[0], "-.") { // 多词符号, 空节点
+			continue
+		}
+		id, err := strconv.Atoi(cols[0])
+		if err != nil || id != len(form)+1 {
+			return nil, fmt.Errorf("conllu: line %d: want ID %d, got %q", line, len(form)+1, cols[0])
+		}
+
+		form = append(form, cols[1])
+		lem, up, xp, fea = append(lem, cols[2]), append(up, cols[3]), append(xp, cols[4]), append(fea, cols[5])
+		for k, c := range map[string]string{"lem": cols[2], "upos": cols[3], "xpos": cols[4], "fea": cols[5], "head": cols[6], "deps": cols[8]} {
+			if c != "_" {
+				seen[k] = true
+			}
+		}
+
+		d := DepTuple{Relation: cols[7]}
+		if cols[6] != "_" {
+			if d.Head, err = strconv.Atoi(cols[6]); err != nil {
+				return nil, fmt.Errorf("conllu: line %d: bad HEAD %q", line, cols[6])
+			}
+		}
+		dep = append(dep, d)
+
+		var arcs []DepTuple
+		if cols[8] != "_" {
+			for _, a := range strings.Split(cols[8], "|") {
+				i := strings.Index(a, ":")
+				if i < 0 {
+					return nil, fmt.Errorf("conllu: line %d: bad DEPS %q", line, cols[8])
+				}
+				h, err := strconv.Atoi(a[:i])
+				if err != nil {
+					return nil, fmt.Errorf("conllu: line %d: bad DEPS %q", line, cols[8])
+				}
+				arcs = append(arcs, DepTuple{Head: h, Relation: a[i+1:]})
+			}
+		}
+		sdp = append(sdp, arcs)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	resp.TokFine = forms
+	if seen["lem"] {
+		resp.Lem = lems
+	}
+	if seen["upos"] {
+		resp.Pos = upos
+	}
+	if seen["xpos"] {
+		*xposCol = xposs
+	}
+	if seen["fea"] {
+		resp.Fea = feas
+	}
+	if seen["head"] {
+		resp.Dep = deps
+	}
+	if seen["deps"] {
+		resp.Sdp = sdps
+	}
+	if len(texts) == len(forms) {
+		resp.Sentences = texts
+	}
+	return resp, nil
+}
+
+func conlluCell(col [][]string, i, j int) string {
+	return conlluField(cell(col, i, j))
+}
+
+// conlluField 空值写作 "_", 列内不允许制表符和换行
+// conlluSpace 列和注释中不能出现的制表符和换行
+var conlluSpace = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+func conlluField(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return conlluSpace.Replace(s)
+}
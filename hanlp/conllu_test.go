@@ -0,0 +1,61 @@
+package hanlp
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCoNLLURoundTrip(t *testing.T) {
+	resp := newSampleResp(t)
+
+	var buf bytes.Buffer
+	if err := resp.WriteCoNLLU(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := "# sent_id = 1\n# text = 晓美焰来到北京立方庭\n" +
+		"1\t晓美焰\t_\t_\tNR\t_\t2\tnsubj\t2:Agt\t_\n"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("WriteCoNLLU =\n%s\nwant prefix\n%s", buf.String(), want)
+	}
+
+	got, err := ReadCoNLLU(&buf, "pos/ctb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.TokFine, resp.TokFine) || !reflect.DeepEqual(got.PosCtb, resp.PosCtb) ||
+		!reflect.DeepEqual(got.Dep, resp.Dep) || !reflect.DeepEqual(got.Sdp, resp.Sdp) {
+		t.Errorf("ReadCoNLLU = %+v", got)
+	}
+	if got.Lem != nil || got.Pos != nil || got.Sentences[0] != "晓美焰来到北京立方庭" {
+		t.Errorf("ReadCoNLLU Lem = %v, Pos = %v, Sentences = %v", got.Lem, got.Pos, got.Sentences)
+	}
+}
+
+func TestCoNLLUXposTask(t *testing.T) {
+	resp := newSampleResp(t)
+	for _, k := range []string{"tok/fine", "lem", "srl"} {
+		if err := resp.WriteCoNLLU(&bytes.Buffer{}, k); err == nil {
+			t.Errorf("WriteCoNLLU(%q): want error", k)
+		}
+		if _, err := ReadCoNLLU(strings.NewReader(""), k); err == nil {
+			t.Errorf("ReadCoNLLU(%q): want error", k)
+		}
+	}
+}
+
+func TestCoNLLUMultilineText(t *testing.T) {
+	resp := &HanResp{TokFine: [][]string{{"第一行", "第二行"}}, Sentences: []string{"第一行\r\n第二行\t"}}
+	var buf bytes.Buffer
+	if err := resp.WriteCoNLLU(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadCoNLLU(&buf, "")
+	if err != nil {
+		t.Fatalf("ReadCoNLLU: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(got.TokFine, resp.TokFine) || got.Sentences[0] != "第一行  第二行" {
+		t.Errorf("ReadCoNLLU = %q, %q", got.TokFine, got.Sentences)
+	}
+}
@@ -0,0 +1,524 @@
+package hanlp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ConNode 成分句法树节点, 叶子为词
+type ConNode struct {
+	Label    string
+	Parent   *ConNode
+	Children []*ConNode
+	Begin    int // 覆盖的词区间, 左闭右开
+	End      int
+}
+
+// ConTree 成分句法树, 空树(如宽松解码的占位)的 Root 为 nil
+type ConTree struct {
+	Root   *ConNode
+	leaves []*ConNode
+}
+
+// NewConTree 由 HanResp.Con 的一个元素构造, 自动去掉无标签的包装节点
+func NewConTree(t ConTuple) *ConTree {
+	tree := &ConTree{}
+	if t.Key == "" && len(t.Value) == 0 {
+		return tree
+	}
+	var build func(t ConTuple, parent *ConNode) *ConNode
+	build = func(t ConTuple, parent *ConNode) *ConNode {
+		n := &ConNode{Label: t.Key, Parent: parent, Begin: len(tree.leaves)}
+		children := conChildren(t)
+		if len(children) == 0 {
+			tree.leaves = append(tree.leaves, n)
+		}
+		for _, c := range children {
+			n.Children = append(n.Children, build(c, n))
+		}
+		n.End = len(tree.leaves)
+		return n
+	}
+
+	root := conNode(t)
+	if root.Key == "" { // 多棵树时补一个空标签的根
+		root = ConTuple{Value: root.Value}
+	}
+	tree.Root = build(root, nil)
+	return tree
+}
+
+// ConTuple 转回 HanResp.Con 的元素格式
+func (t *ConTree) ConTuple() ConTuple {
+	var conv func(n *ConNode) ConTuple
+	conv = func(n *ConNode) ConTuple {
+		children := make([]ConTuple, len(n.Children))
+		for i, c := range n.Children {
+			children[i] = conv(c)
+		}
+		if n.IsLeaf() {
+			return ConTuple{Key: n.Label}
+		}
+		return newConNode(n.Label, children)
+	}
+	if t.Root == nil {
+		return ConTuple{}
+	}
+	return ConTuple{Value: []ConTuple{conv(t.Root)}}
+}
+
+// Leaves 全部叶子(词)
+func (t *ConTree) Leaves() []*ConNode {
+	return t.leaves
+}
+
+// Words 词序列
+func (t *ConTree) Words() []string {
+	if t.Root == nil {
+		return nil
+	}
+	return t.Root.Words()
+}
+
+// PreOrder 先序遍历, fn 返回 false 时不再访问该节点的子节点
+func (t *ConTree) PreOrder(fn func(n *ConNode) bool) {
+	if t.Root != nil {
+		t.Root.PreOrder(fn)
+	}
+}
+
+// PostOrder 后序遍历
+func (t *ConTree) PostOrder(fn func(n *ConNode)) {
+	if t.Root != nil {
+		t.Root.PostOrder(fn)
+	}
+}
+
+// Phrases 指定标签的全部短语(先序), 如 Phrases("NP")
+func (t *ConTree) Phrases(labels ...string) []*ConNode {
+	var re []*ConNode
+	t.PreOrder(func(n *ConNode) bool {
+		if !n.IsLeaf() && containsString(labels, n.Label) {
+			re = append(re, n)
+		}
+		return true
+	})
+	return re
+}
+
+// LCA 最近公共祖先, 节点不在同一棵树时返回 nil
+func (t *ConTree) LCA(a, b *ConNode) *ConNode {
+	seen := make(map[*ConNode]bool)
+	for n := a; n != nil; n = n.Parent {
+		seen[n] = true
+	}
+	for n := b; n != nil; n = n.Parent {
+		if seen[n] {
+			return n
+		}
+	}
+	return nil
+}
+
+// Search 按模式查找节点, 见 CompileConPattern
+func (t *ConTree) Search(pattern string) ([]*ConNode, error) {
+	p, err := CompileConPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return t.Find(p), nil
+}
+
+// Find 查找匹配模式的全部节点(先序)
+func (t *ConTree) Find(p *ConPattern) []*ConNode {
+	var re []*ConNode
+	t.PreOrder(func(n *ConNode) bool {
+		if p.Match(n) {
+			re = append(re, n)
+		}
+		return true
+	})
+	return re
+}
+
+// IsLeaf 是否为词
+func (n *ConNode) IsLeaf() bool {
+	return len(n.Children) == 0
+}
+
+// IsPreterminal 是否为词性节点
+func (n *ConNode) IsPreterminal() bool {
+	return len(n.Children) > 0 && n.Children[0].IsLeaf() && len(n.Children) == 1
+}
+
+// Height 叶子为 0, 词性节点为 1
+func (n *ConNode) Height() int {
+	h := 0
+	for _, c := range n.Children {
+		if ch := c.Height() + 1; ch > h {
+			h = ch
+		}
+	}
+	return h
+}
+
+// Depth 根为 0
+func (n *ConNode) Depth() int {
+	d := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		d++
+	}
+	return d
+}
+
+// Span 覆盖的词区间
+func (n *ConNode) Span() (begin, end int) {
+	return n.Begin, n.End
+}
+
+// Leaves 子树的全部叶子
+func (n *ConNode) Leaves() []*ConNode {
+	var re []*ConNode
+	n.PreOrder(func(c *ConNode) bool {
+		if c.IsLeaf() {
+			re = append(re, c)
+		}
+		return true
+	})
+	return re
+}
+
+// Words 子树的词序列
+func (n *ConNode) Words() []string {
+	leaves := n.Leaves()
+	re := make([]string, len(leaves))
+	for i, l := range leaves {
+		re[i] = l.Label
+	}
+	return re
+}
+
+// Text 子树覆盖的文本
+func (n *ConNode) Text() string {
+	return joinTokens(n.Words())
+}
+
+// PreOrder 先序遍历, fn 返回 false 时不再访问该节点的子节点
+func (n *ConNode) PreOrder(fn func(n *ConNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.PreOrder(fn)
+	}
+}
+
+// PostOrder 后序遍历
+func (n *ConNode) PostOrder(fn func(n *ConNode)) {
+	for _, c := range n.Children {
+		c.PostOrder(fn)
+	}
+	fn(n)
+}
+
+// Dominates n 是否为 m 的祖先(不含自身)
+func (n *ConNode) Dominates(m *ConNode) bool {
+	for p := m.Parent; p != nil; p = p.Parent {
+		if p == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Sisters 同一父节点下的其它节点
+func (n *ConNode) Sisters() []*ConNode {
+	if n.Parent == nil {
+		return nil
+	}
+	var re []*ConNode
+	for _, c := range n.Parent.Children {
+		if c != n {
+			re = append(re, c)
+		}
+	}
+	return re
+}
+
+func (n *ConNode) index() int {
+	if n.Parent == nil {
+		return -1
+	}
+	for i, c := range n.Parent.Children {
+		if c == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// ConPattern 仿 tregex 的结构查询模式
+type ConPattern struct {
+	desc      func(label string) bool
+	relations []conRelation
+}
+
+type conRelation struct {
+	op     string
+	negate bool
+	target *ConPattern
+}
+
+/*
+CompileConPattern 编译结构查询模式, 语法为 tregex 的子集:
+
+	节点:  NP  精确标签;  NP|VP  多选;  /^N/  正则;  __  任意节点;
+	       含特殊字符的标签用反斜杠转义或加双引号: PRP\$  "PRP$"|WP\$
+	关系:  A < B   B 是 A 的子节点        A > B   B 是 A 的父节点
+	       A << B  A 支配 B(祖先)         A >> B  A 被 B 支配
+	       A $ B   B 是 A 的姐妹节点      A $+ B  B 紧邻 A 右侧   A $- B  B 紧邻 A 左侧
+	       A !< B  关系取反
+	多个关系都作用于最前面的节点: NP < NN < JJ; 括号用于嵌套: NP < (PP < P)
+*/
+func CompileConPattern(s string) (*ConPattern, error) {
+	p := &conPatternParser{toks: tokenizeConPattern(s)}
+	re, err := p.node()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("con pattern: unexpected %q", p.toks[p.pos])
+	}
+	return re, nil
+}
+
+// Match 节点是否匹配
+func (p *ConPattern) Match(n *ConNode) bool {
+	if !p.desc(n.Label) {
+		return false
+	}
+	for _, r := range p.relations {
+		if r.holds(n) == r.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (r conRelation) holds(n *ConNode) bool {
+	var candidates []*ConNode
+	switch r.op {
+	case "<":
+		candidates = n.Children
+	case ">":
+		if n.Parent != nil {
+			candidates = []*ConNode{n.Parent}
+		}
+	case "<<":
+		for _, c := range n.Children {
+			c.PreOrder(func(m *ConNode) bool {
+				candidates = append(candidates, m)
+				return true
+			})
+		}
+	case ">>":
+		for p := n.Parent; p != nil; p = p.Parent {
+			candidates = append(candidates, p)
+		}
+	case "$":
+		candidates = n.Sisters()
+	case "$+", "$-":
+		if i := n.index(); i >= 0 {
+			j := i + 1
+			if r.op == "$-" {
+				j = i - 1
+			}
+			if j >= 0 && j < len(n.Parent.Children) {
+				candidates = []*ConNode{n.Parent.Children[j]}
+			}
+		}
+	}
+
+	for _, c := range candidates {
+		if r.target.Match(c) {
+			return true
+		}
+	}
+	return false
+}
+
+var conRelationOps = []string{"<<", ">>", "$+", "$-", "<", ">", "$"}
+
+func tokenizeConPattern(s string) []string {
+	var toks []string
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '!':
+			toks = append(toks, string(r))
+			i++
+		case r == '/':
+			j := i + 1
+			for j < len(rs) && rs[j] != '/' {
+				if rs[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j > len(rs) {
+				j = len(rs)
+			}
+			toks = append(toks, string(rs[i:minInt(j+1, len(rs))]))
+			i = j + 1
+		default:
+			matched := false
+			for _, op := range conRelationOps {
+				if strings.HasPrefix(string(rs[i:]), op) {
+					toks = append(toks, op)
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			j, quoted := i, false
+			for j < len(rs) && (quoted || !unicode.IsSpace(rs[j]) && !strings.ContainsRune("()!<>$/", rs[j])) {
+				switch {
+				case rs[j] == '"':
+					quoted = !quoted
+				case rs[j] == '\\' && j+1 < len(rs):
+					j++
+				}
+				j++
+			}
+			toks = append(toks, string(rs[i:j]))
+			i = j
+		}
+	}
+	return toks
+}
+
+type conPatternParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *conPatternParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+// node 节点描述及其后的全部关系
+func (p *conPatternParser) node() (*ConPattern, error) {
+	desc, err := p.desc()
+	if err != nil {
+		return nil, err
+	}
+	re := &ConPattern{desc: desc}
+	for {
+		negate := false
+		if p.peek() == "!" {
+			negate = true
+			p.pos++
+		}
+		op := p.peek()
+		if !containsString(conRelationOps, op) {
+			if negate {
+				return nil, fmt.Errorf("con pattern: want relation after '!', got %q", op)
+			}
+			return re, nil
+		}
+		p.pos++
+
+		var target *ConPattern
+		if p.peek() == "(" {
+			p.pos++
+			if target, err = p.node(); err != nil {
+				return nil, err
+			}
+			if p.peek() != ")" {
+				return nil, fmt.Errorf("con pattern: want ')', got %q", p.peek())
+			}
+			p.pos++
+		} else {
+			d, err := p.desc()
+			if err != nil {
+				return nil, err
+			}
+			target = &ConPattern{desc: d}
+		}
+		re.relations = append(re.relations, conRelation{op: op, negate: negate, target: target})
+	}
+}
+
+// desc 节点描述
+func (p *conPatternParser) desc() (func(string) bool, error) {
+	tok := p.peek()
+	switch {
+	case tok == "" || tok == "(" || tok == ")" || tok == "!" || containsString(conRelationOps, tok):
+		return nil, fmt.Errorf("con pattern: want node description, got %q", tok)
+	case tok == "__":
+		p.pos++
+		return func(string) bool { return true }, nil
+	case strings.HasPrefix(tok, "/"):
+		if len(tok) < 2 || !strings.HasSuffix(tok, "/") {
+			return nil, fmt.Errorf("con pattern: unterminated regex %q", tok)
+		}
+		re, err := regexp.Compile(tok[1 : len(tok)-1])
+		if err != nil {
+			return nil, fmt.Errorf("con pattern: %v", err)
+		}
+		p.pos++
+		return re.MatchString, nil
+	}
+	labels, err := splitConLabels(tok)
+	if err != nil {
+		return nil, err
+	}
+	p.pos++
+	return func(label string) bool { return containsString(labels, label) }, nil
+}
+
+// splitConLabels 以 | 分隔标签, 去掉转义的反斜杠和双引号
+func splitConLabels(tok string) ([]string, error) {
+	var (
+		re     []string
+		sb     strings.Builder
+		quoted bool
+	)
+	rs := []rune(tok)
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; {
+		case r == '"':
+			quoted = !quoted
+		case r == '\\' && i+1 < len(rs):
+			i++
+			sb.WriteRune(rs[i])
+		case r == '|' && !quoted:
+			re = append(re, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("con pattern: unterminated quote %s", tok)
+	}
+	return append(re, sb.String()), nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
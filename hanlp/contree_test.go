@@ -0,0 +1,92 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConTree(t *testing.T) {
+	con, err := ParsePTB("(TOP (IP (NP (NR 晓美焰)) (VP (VV 来到) (NP (NR 北京) (NN 立方庭))) (PU 。)))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := NewConTree(con)
+	if got := tree.Words(); !reflect.DeepEqual(got, []string{"晓美焰", "来到", "北京", "立方庭", "。"}) {
+		t.Errorf("Words = %v", got)
+	}
+	if tree.ConTuple().PTB() != con.PTB() {
+		t.Errorf("ConTuple = %s", tree.ConTuple().PTB())
+	}
+
+	nps := tree.Phrases("NP")
+	if len(nps) != 2 || nps[1].Text() != "北京立方庭" || nps[1].Begin != 2 || nps[1].End != 4 {
+		t.Fatalf("Phrases(NP) = %v", nps)
+	}
+	if tree.Root.Height() != 5 || nps[1].Depth() != 3 {
+		t.Errorf("Height = %d, Depth = %d", tree.Root.Height(), nps[1].Depth())
+	}
+	leaves := tree.Leaves()
+	if lca := tree.LCA(leaves[1], leaves[3]); lca.Label != "VP" {
+		t.Errorf("LCA = %s", lca.Label)
+	}
+
+	for pattern, want := range map[string][]string{
+		"NP":                  {"晓美焰", "北京立方庭"},
+		"NP > VP":             {"北京立方庭"},
+		"NP !> VP":            {"晓美焰"},
+		"/^V/ << NN":          {"来到北京立方庭"},
+		"__ < (NR < 北京) $ VV": {"北京立方庭"},
+		"NP $+ VP":            {"晓美焰"},
+		"NR|NN >> VP":         {"北京", "立方庭"},
+	} {
+		nodes, err := tree.Search(pattern)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", pattern, err)
+		}
+		var got []string
+		for _, n := range nodes {
+			got = append(got, n.Text())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+	for _, bad := range []string{"", "NP <", "NP < (VP", "NP !", "/[/"} {
+		if _, err := CompileConPattern(bad); err == nil {
+			t.Errorf("CompileConPattern(%q) want error", bad)
+		}
+	}
+}
+
+func TestConPatternEscapedLabel(t *testing.T) {
+	con, err := ParsePTB("(ROOT (S (NP (PRP$ his) (NN dog)) (VP (VBD barked))))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := NewConTree(con)
+	for _, pattern := range []string{`PRP\$`, `"PRP$"`, `"PRP$"|WP\$ $+ NN`, `NP < "PRP$"`} {
+		nodes, err := tree.Search(pattern)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", pattern, err)
+		}
+		if len(nodes) != 1 {
+			t.Errorf("Search(%q) = %v, want 1 node", pattern, nodes)
+		}
+	}
+	if _, err := CompileConPattern(`"PRP$`); err == nil {
+		t.Errorf("want error for unterminated quote")
+	}
+}
+
+func TestConTreeEmpty(t *testing.T) {
+	tree := NewConTree(ConTuple{})
+	if tree.Root != nil || len(tree.Leaves()) != 0 || tree.Words() != nil {
+		t.Errorf("empty tree = %+v", tree)
+	}
+	if nodes, err := tree.Search("__"); err != nil || len(nodes) != 0 {
+		t.Errorf("Search = %v, %v", nodes, err)
+	}
+	if got := tree.ConTuple(); got.Key != "" || got.Value != nil {
+		t.Errorf("ConTuple = %+v", got)
+	}
+}
@@ -0,0 +1,227 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// CorefMention 指代消解中的一次提及. Begin/End 为展平后全文的词下标, 左闭右开
+type CorefMention struct {
+	Text  string `json:"text"`
+	Begin int    `json:"begin"`
+	End   int    `json:"end"`
+}
+
+// CorefCluster 指向同一实体的提及
+type CorefCluster []CorefMention
+
+// CorefResult 指代消解结果
+type CorefResult struct {
+	Clusters []CorefCluster `json:"clusters"`
+	Tokens   []string       `json:"tokens"` // 展平后的全文分词
+}
+
+// CorefResolution 代词及其先行词
+type CorefResolution struct {
+	Pronoun    CorefMention
+	Antecedent CorefMention
+}
+
+// UnmarshalJSON HanLP 格式 [text, begin, end]
+func (m *CorefMention) UnmarshalJSON(b []byte) error {
+	var t []interface{}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	if len(t) != 3 {
+		return fmt.Errorf("coref mention: want [text, begin, end], got %s", b)
+	}
+	text, ok1 := t[0].(string)
+	begin, ok2 := t[1].(float64)
+	end, ok3 := t[2].(float64)
+	if !ok1 || !ok2 || !ok3 {
+		return fmt.Errorf("coref mention: want [text, begin, end], got %s", b)
+	}
+	*m = CorefMention{Text: text, Begin: int(begin), End: int(end)}
+	return nil
+}
+
+// MarshalJSON HanLP 格式 [text, begin, end]
+func (m CorefMention) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{m.Text, m.Begin, m.End})
+}
+
+// pronouns 常见中英文代词
+var pronouns = map[string]bool{
+	"我": true, "你": true, "您": true, "他": true, "她": true, "它": true,
+	"我们": true, "你们": true, "您们": true, "他们": true, "她们": true, "它们": true, "咱": true, "咱们": true,
+	"自己": true, "其": true, "该": true, "此": true, "这": true, "那": true, "这个": true, "那个": true,
+	"本人": true, "对方": true, "人家": true, "大家": true,
+	"i": true, "me": true, "my": true, "mine": true, "myself": true,
+	"you": true, "your": true, "yours": true, "yourself": true, "yourselves": true,
+	"he": true, "him": true, "his": true, "himself": true,
+	"she": true, "her": true, "hers": true, "herself": true,
+	"it": true, "its": true, "itself": true,
+	"we": true, "us": true, "our": true, "ours": true, "ourselves": true,
+	"they": true, "them": true, "their": true, "theirs": true, "themselves": true,
+	"this": true, "that": true, "these": true, "those": true,
+}
+
+// IsPronoun 是否为代词
+func IsPronoun(s string) bool {
+	return pronouns[strings.ToLower(strings.TrimSpace(s))]
+}
+
+// IsPronoun 提及是否为代词
+func (m CorefMention) IsPronoun() bool {
+	return IsPronoun(m.Text)
+}
+
+// Representative 簇中第一个非代词提及, 全为代词时返回第一个提及
+func (c CorefCluster) Representative() (CorefMention, bool) {
+	if len(c) == 0 {
+		return CorefMention{}, false
+	}
+	for _, m := range c {
+		if !m.IsPronoun() {
+			return m, true
+		}
+	}
+	return c[0], true
+}
+
+// Antecedent 提及 m 之前最近的非代词提及, 没有时(后指)退回簇的代表提及
+func (c CorefCluster) Antecedent(m CorefMention) (CorefMention, bool) {
+	found := false
+	var re CorefMention
+	for _, v := range c {
+		if v.Begin >= m.Begin {
+			break
+		}
+		if !v.IsPronoun() {
+			re, found = v, true
+		}
+	}
+	if found {
+		return re, true
+	}
+
+	re, ok := c.Representative()
+	if !ok || re == m || re.IsPronoun() {
+		return CorefMention{}, false
+	}
+	return re, true
+}
+
+// Cluster 返回包含指定提及的簇
+func (r *CorefResult) Cluster(begin, end int) (CorefCluster, bool) {
+	for _, c := range r.Clusters {
+		for _, m := range c {
+			if m.Begin == begin && m.End == end {
+				return c, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ResolvePronouns 将每个代词提及解析到其先行词, 找不到先行词的代词被忽略
+func (r *CorefResult) ResolvePronouns() []CorefResolution {
+	var re []CorefResolution
+	for _, c := range r.Clusters {
+		for _, m := range c {
+			if !m.IsPronoun() {
+				continue
+			}
+			if a, ok := c.Antecedent(m); ok {
+				re = append(re, CorefResolution{Pronoun: m, Antecedent: a})
+			}
+		}
+	}
+	return re
+}
+
+/*
+指代消解
+Coreference resolution is the task of clustering mentions in text that refer to the same underlying
+
+	real world entities.
+
+	Args:
+	    text: A piece of text, usually a document without tokenization.
+	    tokens: A list of sentences where each sentence is a list of tokens.
+	    language: The language of input text. ``None`` to use the default language.
+
+	Returns:
+	    When ``text`` is specified, return the clusters and tokens. Otherwise just the clusters, In this case, you need to ``sum(tokens, [])`` in order to match the span indices with tokens
+
+	Examples::
+
+	    HanLP.coreference_resolution('我姐送我她的猫。我很喜欢它。')
+	    # Output:
+	    {'clusters': [
+	                  [['我', 0, 1], ['我', 3, 4], ['我', 8, 9]], # 指代说话人
+	                  [['我姐', 0, 2], ['她', 4, 5]],             # 指代说话人的姐姐
+	                  [['她的猫', 4, 7], ['它', 11, 12]]],        # 指代说话人的姐姐的猫
+	     'tokens': ['我', '姐', '送', '我', '她', '的', '猫', '。',
+	                '我', '很', '喜欢', '它', '。']}
+*/
+func (h *hanlp) CoreferenceResolution(text string, opts ...Option) (*CorefResult, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	req := &HanReq{
+		Text:     text,
+		Language: options.Language, // (zh,mnt)
+	}
+
+	b, err := h.post(options, "/coreference_resolution", req)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalCoref([]byte(b), nil)
+}
+
+// CoreferenceResolutionTokens 指代消解(已分词输入), 返回结果的 Tokens 为展平后的 tokens
+func (h *hanlp) CoreferenceResolutionTokens(tokens [][]string, opts ...Option) (*CorefResult, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	req := &HanReq{
+		Tokens:   tokens,
+		Language: options.Language, // (zh,mnt)
+	}
+
+	b, err := h.post(options, "/coreference_resolution", req)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalCoref([]byte(b), tokens)
+}
+
+// unmarshalCoref 兼容 {"clusters": ..., "tokens": ...} 与仅返回 clusters 两种格式
+func unmarshalCoref(b []byte, tokens [][]string) (*CorefResult, error) {
+	re := &CorefResult{}
+	if err := json.Unmarshal(b, re); err != nil {
+		var clusters []CorefCluster
+		if e := json.Unmarshal(b, &clusters); e != nil {
+			mylog.Error(err)
+			return nil, err
+		}
+		re.Clusters = clusters
+	}
+
+	if len(re.Tokens) == 0 {
+		for _, v := range tokens {
+			re.Tokens = append(re.Tokens, v...)
+		}
+	}
+	return re, nil
+}
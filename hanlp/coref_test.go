@@ -0,0 +1,64 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// corefSample HanLP 文档中 coreference_resolution 的示例输出
+const corefSample = `{"clusters": [
+	[["我", 0, 1], ["我", 3, 4], ["我", 8, 9]],
+	[["我姐", 0, 2], ["她", 4, 5]],
+	[["她的猫", 4, 7], ["它", 11, 12]]],
+ "tokens": ["我", "姐", "送", "我", "她", "的", "猫", "。", "我", "很", "喜欢", "它", "。"]}`
+
+func TestCorefMentionJSON(t *testing.T) {
+	var m CorefMention
+	if err := json.Unmarshal([]byte(`["她的猫", 4, 7]`), &m); err != nil || m != (CorefMention{"她的猫", 4, 7}) {
+		t.Errorf("Unmarshal = %+v, %v", m, err)
+	}
+	b, err := json.Marshal(m)
+	if err != nil || string(b) != `["她的猫",4,7]` {
+		t.Errorf("Marshal = %s, %v", b, err)
+	}
+	for _, bad := range []string{`["她", 4]`, `[4, 5, 6]`, `{"text": "她"}`} {
+		if err := json.Unmarshal([]byte(bad), &m); err == nil {
+			t.Errorf("Unmarshal(%s) want error", bad)
+		}
+	}
+}
+
+func TestResolvePronouns(t *testing.T) {
+	r, err := unmarshalCoref([]byte(corefSample), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Clusters) != 3 || len(r.Tokens) != 13 {
+		t.Fatalf("result = %+v", r)
+	}
+
+	// 全为代词的簇(说话人)没有先行词
+	want := []CorefResolution{
+		{Pronoun: CorefMention{"她", 4, 5}, Antecedent: CorefMention{"我姐", 0, 2}},
+		{Pronoun: CorefMention{"它", 11, 12}, Antecedent: CorefMention{"她的猫", 4, 7}},
+	}
+	if got := r.ResolvePronouns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolvePronouns = %+v, want %+v", got, want)
+	}
+
+	// 后指: 代词在先行词之前时退回代表提及
+	c := CorefCluster{{"他", 0, 1}, {"张三", 3, 5}, {"他", 7, 8}}
+	if a, ok := c.Antecedent(c[0]); !ok || a != c[1] {
+		t.Errorf("Antecedent(cataphora) = %+v, %v", a, ok)
+	}
+	if a, ok := c.Antecedent(c[2]); !ok || a != c[1] {
+		t.Errorf("Antecedent = %+v, %v", a, ok)
+	}
+	if _, ok := r.Clusters[0].Antecedent(r.Clusters[0][1]); ok {
+		t.Error("all-pronoun cluster want no antecedent")
+	}
+	if cl, ok := r.Cluster(11, 12); !ok || cl[0].Text != "她的猫" {
+		t.Errorf("Cluster(11, 12) = %v, %v", cl, ok)
+	}
+}
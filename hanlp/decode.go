@@ -0,0 +1,502 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// DecodeError 解码 HanLP 返回结果时的错误位置
+type DecodeError struct {
+	Task     string // 任务键, 如 ner/msra
+	Sentence int    // 句子下标, -1 表示整个任务
+	Path     []int  // 句内元素下标, srl 为 [谓词, 论元], sdp 为 [词, 弧], con 为子树路径
+	Position int    // 元组内位置, -1 表示整个元素
+	Msg      string
+}
+
+func (e *DecodeError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "hanlp: decode %q", e.Task)
+	if e.Sentence >= 0 {
+		fmt.Fprintf(&sb, " sentence %d", e.Sentence)
+	}
+	if len(e.Path) > 0 {
+		fmt.Fprintf(&sb, " element %v", e.Path)
+	}
+	if e.Position >= 0 {
+		fmt.Fprintf(&sb, " position %d", e.Position)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Msg)
+	return sb.String()
+}
+
+// placeholderHead 宽松解码时格式错误的依存弧以 Head = -1 占位, ValidateDep 等校验会拒绝
+const placeholderHead = -1
+
+// decoder 严格模式遇错即返回, 宽松模式记录警告: 与词对齐的元素(词, 词性, 依存弧)以 "" 或 Head = -1 占位,
+// 实体和论元等区间元素直接跳过
+type decoder struct {
+	lenient  bool
+	warnings []*DecodeError
+}
+
+func (d *decoder) fail(e *DecodeError) error {
+	if d.lenient {
+		d.warnings = append(d.warnings, e)
+		return nil
+	}
+	return e
+}
+
+// UnmarshalHanResp marshal obj, 结果格式错误时返回 *DecodeError
+func UnmarshalHanResp(b []byte) (*HanResp, error) {
+	return unmarshalHanResp(b, false)
+}
+
+// UnmarshalHanRespLenient 格式错误的元素记录在 HanResp.Warnings 中: 与词对齐的以 "" 或 Head = -1 的弧占位, 其余跳过
+func UnmarshalHanRespLenient(b []byte) (*HanResp, error) {
+	return unmarshalHanResp(b, true)
+}
+
+func unmarshalHanResp(b []byte, lenient bool) (*HanResp, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		mylog.Error(err)
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	d := &decoder{lenient: lenient}
+	resp := &HanResp{raw: raw}
+	for _, k := range keys {
+		if err := d.decodeTask(resp, k, raw[k]); err != nil {
+			mylog.Error(err)
+			return nil, err
+		}
+	}
+	resp.Warnings = d.warnings
+	return resp, nil
+}
+
+// decodeTask 内置任务解码到类型化字段, 其它任务键交给已注册的解码器
+func (d *decoder) decodeTask(resp *HanResp, task string, b json.RawMessage) error {
+	if fn, ok := getTaskDecoder(task); ok {
+		out, err := fn(b)
+		if err != nil {
+			return d.fail(&DecodeError{Task: task, Sentence: -1, Position: -1, Msg: err.Error()})
+		}
+		if resp.tasks == nil {
+			resp.tasks = make(map[string]interface{})
+		}
+		resp.tasks[task] = out
+	}
+
+	var x interface{}
+	if err := json.Unmarshal(b, &x); err != nil {
+		return d.fail(&DecodeError{Task: task, Sentence: -1, Position: -1, Msg: err.Error()})
+	}
+	if x == nil {
+		return nil
+	}
+
+	var err error
+	if p := resp.column(task); p != nil {
+		*p, err = d.decodeStrings(task, x)
+	} else if p := resp.nerColumn(task); p != nil {
+		*p, err = d.decodeNer(task, x)
+	} else if p := resp.sdpColumn(task); p != nil {
+		*p, err = d.decodeSdp(task, x)
+	} else {
+		switch task {
+		case "srl":
+			resp.Srl, err = d.decodeSrl(task, x)
+		case "dep":
+			resp.Dep, err = d.decodeDep(task, x)
+		case "con":
+			resp.Con, err = d.decodeCon(task, x)
+		}
+	}
+	return err
+}
+
+// sentences 最外层为句子列表
+func (d *decoder) sentences(task string, x interface{}) ([]interface{}, error) {
+	sents, ok := x.([]interface{})
+	if !ok {
+		return nil, d.fail(&DecodeError{Task: task, Sentence: -1, Position: -1, Msg: "want list of sentences, got " + jsonType(x)})
+	}
+	return sents, nil
+}
+
+// elements 句子内的元素列表, 格式错误时返回 nil, 宽松模式下保留空句以对齐
+func (d *decoder) elements(task string, i int, path []int, x interface{}) ([]interface{}, bool, error) {
+	list, ok := x.([]interface{})
+	if !ok {
+		return nil, false, d.fail(&DecodeError{Task: task, Sentence: i, Path: path, Position: -1, Msg: "want list, got " + jsonType(x)})
+	}
+	return list, true, nil
+}
+
+func (d *decoder) decodeStrings(task string, x interface{}) ([][]string, error) {
+	sents, err := d.sentences(task, x)
+	if sents == nil {
+		return nil, err
+	}
+
+	re := make([][]string, 0, len(sents))
+	for i, s := range sents {
+		list, ok, err := d.elements(task, i, nil, s)
+		if err != nil {
+			return nil, err
+		}
+		tmp := make([]string, 0, len(list))
+		if ok {
+			for j, v := range list {
+				str, ok := v.(string)
+				if !ok {
+					if err = d.fail(&DecodeError{Task: task, Sentence: i, Path: []int{j}, Position: -1, Msg: "want string, got " + jsonType(v)}); err != nil {
+						return nil, err
+					}
+				}
+				tmp = append(tmp, str)
+			}
+		}
+		re = append(re, tmp)
+	}
+	return re, nil
+}
+
+func (d *decoder) decodeNer(task string, x interface{}) ([][]NerTuple, error) {
+	sents, err := d.sentences(task, x)
+	if sents == nil {
+		return nil, err
+	}
+
+	re := make([][]NerTuple, 0, len(sents))
+	for i, s := range sents {
+		list, ok, err := d.elements(task, i, nil, s)
+		if err != nil {
+			return nil, err
+		}
+		var tmp []NerTuple
+		if ok {
+			for j, v := range list {
+				t, pos, e := nerTupleOf(v)
+				if e != nil {
+					if err = d.fail(&DecodeError{Task: task, Sentence: i, Path: []int{j}, Position: pos, Msg: e.Error()}); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				tmp = append(tmp, t)
+			}
+		}
+		re = append(re, tmp)
+	}
+	return re, nil
+}
+
+func (d *decoder) decodeSrl(task string, x interface{}) ([][][]SrlTuple, error) {
+	sents, err := d.sentences(task, x)
+	if sents == nil {
+		return nil, err
+	}
+
+	re := make([][][]SrlTuple, 0, len(sents))
+	for i, s := range sents {
+		frames, ok, err := d.elements(task, i, nil, s)
+		if err != nil {
+			return nil, err
+		}
+		var tmp [][]SrlTuple
+		if ok {
+			for j, f := range frames {
+				list, ok, err := d.elements(task, i, []int{j}, f)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				var tmp1 []SrlTuple
+				for k, v := range list {
+					t, pos, e := srlTupleOf(v)
+					if e != nil {
+						if err = d.fail(&DecodeError{Task: task, Sentence: i, Path: []int{j, k}, Position: pos, Msg: e.Error()}); err != nil {
+							return nil, err
+						}
+						continue
+					}
+					tmp1 = append(tmp1, t)
+				}
+				tmp = append(tmp, tmp1)
+			}
+		}
+		re = append(re, tmp)
+	}
+	return re, nil
+}
+
+func (d *decoder) decodeDep(task string, x interface{}) ([][]DepTuple, error) {
+	sents, err := d.sentences(task, x)
+	if sents == nil {
+		return nil, err
+	}
+
+	re := make([][]DepTuple, 0, len(sents))
+	for i, s := range sents {
+		list, ok, err := d.elements(task, i, nil, s)
+		if err != nil {
+			return nil, err
+		}
+		var tmp []DepTuple
+		if ok {
+			for j, v := range list {
+				t, pos, e := depTupleOf(v)
+				if e != nil {
+					if err = d.fail(&DecodeError{Task: task, Sentence: i, Path: []int{j}, Position: pos, Msg: e.Error()}); err != nil {
+						return nil, err
+					}
+					t = DepTuple{Head: placeholderHead}
+				}
+				tmp = append(tmp, t)
+			}
+		}
+		re = append(re, tmp)
+	}
+	return re, nil
+}
+
+func (d *decoder) decodeSdp(task string, x interface{}) ([][][]DepTuple, error) {
+	sents, err := d.sentences(task, x)
+	if sents == nil {
+		return nil, err
+	}
+
+	re := make([][][]DepTuple, 0, len(sents))
+	for i, s := range sents {
+		toks, ok, err := d.elements(task, i, nil, s)
+		if err != nil {
+			return nil, err
+		}
+		var tmp [][]DepTuple
+		if ok {
+			for j, tok := range toks {
+				list, ok, err := d.elements(task, i, []int{j}, tok)
+				if err != nil {
+					return nil, err
+				}
+				var tmp1 []DepTuple
+				if ok {
+					for k, v := range list {
+						t, pos, e := depTupleOf(v)
+						if e != nil {
+							if err = d.fail(&DecodeError{Task: task, Sentence: i, Path: []int{j, k}, Position: pos, Msg: e.Error()}); err != nil {
+								return nil, err
+							}
+							t = DepTuple{Head: placeholderHead}
+						}
+						tmp1 = append(tmp1, t)
+					}
+				}
+				tmp = append(tmp, tmp1) // 保持与词对齐
+			}
+		}
+		re = append(re, tmp)
+	}
+	return re, nil
+}
+
+func (d *decoder) decodeCon(task string, x interface{}) ([]ConTuple, error) {
+	sents, err := d.sentences(task, x)
+	if sents == nil {
+		return nil, err
+	}
+
+	re := make([]ConTuple, 0, len(sents))
+	for i, s := range sents {
+		list, ok, err := d.elements(task, i, nil, s)
+		if err != nil {
+			return nil, err
+		}
+		tree := ConTuple{}
+		if ok {
+			value, path, e := dealCon(list, nil)
+			if e != nil {
+				if err = d.fail(&DecodeError{Task: task, Sentence: i, Path: path, Position: -1, Msg: e.Error()}); err != nil {
+					return nil, err
+				}
+			} else {
+				tree.Value = value
+			}
+		}
+		re = append(re, tree) // 每句一个无标签的包装节点
+	}
+	return re, nil
+}
+
+// dealCon [label, [children...]] 或 [[...], [...]] 转为 ConTuple, 出错时返回子树路径
+func dealCon(info []interface{}, path []int) (re []ConTuple, _ []int, err error) {
+	if len(info) == 0 {
+		return nil, nil, nil
+	}
+
+	switch t := info[0].(type) {
+	case string:
+		{
+			if len(info) > 2 {
+				return nil, path, fmt.Errorf("want [label, children], got %d elements", len(info))
+			}
+			tmp1 := ConTuple{
+				Key: t,
+			}
+			if len(info) == 2 {
+				children, ok := info[1].([]interface{})
+				if !ok {
+					return nil, append(path, 1), fmt.Errorf("want list of children, got %s", jsonType(info[1]))
+				}
+				value, where, err := dealCon(children, append(path, 1))
+				if err != nil {
+					return nil, where, err
+				}
+				tmp1.Value = value
+			}
+			re = append(re, tmp1)
+		}
+	case []interface{}:
+		{
+			for i, t1 := range info {
+				sub, ok := t1.([]interface{})
+				if !ok {
+					return nil, append(path, i), fmt.Errorf("want subtree, got %s", jsonType(t1))
+				}
+				value, where, err := dealCon(sub, append(path, i))
+				if err != nil {
+					return nil, where, err
+				}
+				re = append(re, ConTuple{Value: value})
+			}
+		}
+	default:
+		return nil, path, fmt.Errorf("want label or subtree, got %s", jsonType(t))
+	}
+
+	return re, nil, nil
+}
+
+// tuple 定长元组, 出错位置为 -1
+func tuple(v interface{}, n int) ([]interface{}, error) {
+	t, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("want %d-tuple, got %s", n, jsonType(v))
+	}
+	if len(t) != n {
+		return nil, fmt.Errorf("want %d-tuple, got %d elements", n, len(t))
+	}
+	return t, nil
+}
+
+func tupleString(t []interface{}, i int) (string, error) {
+	s, ok := t[i].(string)
+	if !ok {
+		return "", fmt.Errorf("want string, got %s", jsonType(t[i]))
+	}
+	return s, nil
+}
+
+func tupleInt(t []interface{}, i int) (int, error) {
+	f, ok := t[i].(float64)
+	if !ok {
+		return 0, fmt.Errorf("want integer, got %s", jsonType(t[i]))
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("want integer, got %v", f)
+	}
+	return int(f), nil
+}
+
+// nerTupleOf [entity, type, begin, end], 返回出错位置
+func nerTupleOf(v interface{}) (re NerTuple, pos int, err error) {
+	t, err := tuple(v, 4)
+	if err != nil {
+		return re, -1, err
+	}
+	if re.Entity, err = tupleString(t, 0); err != nil {
+		return re, 0, err
+	}
+	if re.Type, err = tupleString(t, 1); err != nil {
+		return re, 1, err
+	}
+	if re.Begin, err = tupleInt(t, 2); err != nil {
+		return re, 2, err
+	}
+	if re.End, err = tupleInt(t, 3); err != nil {
+		return re, 3, err
+	}
+	return re, -1, nil
+}
+
+// srlTupleOf [arg/pred, label, begin, end], 返回出错位置
+func srlTupleOf(v interface{}) (re SrlTuple, pos int, err error) {
+	t, err := tuple(v, 4)
+	if err != nil {
+		return re, -1, err
+	}
+	if re.ArgPred, err = tupleString(t, 0); err != nil {
+		return re, 0, err
+	}
+	if re.Label, err = tupleString(t, 1); err != nil {
+		return re, 1, err
+	}
+	if re.Begin, err = tupleInt(t, 2); err != nil {
+		return re, 2, err
+	}
+	if re.End, err = tupleInt(t, 3); err != nil {
+		return re, 3, err
+	}
+	return re, -1, nil
+}
+
+// depTupleOf [head, relation], 返回出错位置
+func depTupleOf(v interface{}) (re DepTuple, pos int, err error) {
+	t, err := tuple(v, 2)
+	if err != nil {
+		return re, -1, err
+	}
+	if re.Head, err = tupleInt(t, 0); err != nil {
+		return re, 0, err
+	}
+	if re.Relation, err = tupleString(t, 1); err != nil {
+		return re, 1, err
+	}
+	return re, -1, nil
+}
+
+func jsonType(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", t)
+	case float64:
+		return fmt.Sprintf("number %v", t)
+	case bool:
+		return fmt.Sprintf("bool %v", t)
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,178 @@
+package hanlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleResp = `{
+  "tok/fine": [["晓美焰", "来到", "北京", "立方庭"]],
+  "pos/ctb": [["NR", "VV", "NR", "NR"]],
+  "ner/msra": [[["晓美焰", "PERSON", 0, 1], ["北京立方庭", "LOCATION", 2, 4]]],
+  "srl": [[[["晓美焰", "ARG0", 0, 1], ["来到", "PRED", 1, 2], ["北京立方庭", "ARG1", 2, 4]]]],
+  "dep": [[[2, "nsubj"], [0, "root"], [4, "nn"], [2, "dobj"]]],
+  "sdp": [[[[2, "Agt"]], [[0, "Root"]], [[4, "Nmod"]], [[2, "Lfin"]]]],
+  "con": [["TOP", [["IP", [["NP", [["NR", ["晓美焰"]]]], ["VP", [["VV", ["来到"]], ["NP", [["NR", ["北京"]], ["NR", ["立方庭"]]]]]]]]]]]
+}`
+
+// newSampleResp 解码 sampleResp
+func newSampleResp(t *testing.T) *HanResp {
+	t.Helper()
+	resp, err := UnmarshalHanResp([]byte(sampleResp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestUnmarshalHanResp(t *testing.T) {
+	resp, err := UnmarshalHanResp([]byte(sampleResp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []NerTuple{{"晓美焰", "PERSON", 0, 1}, {"北京立方庭", "LOCATION", 2, 4}}; !reflect.DeepEqual(resp.NerMsra[0], want) {
+		t.Errorf("NerMsra = %v, want %v", resp.NerMsra[0], want)
+	}
+	if want := (DepTuple{Head: 2, Relation: "nsubj"}); resp.Dep[0][0] != want {
+		t.Errorf("Dep[0][0] = %v, want %v", resp.Dep[0][0], want)
+	}
+	if len(resp.Sdp[0]) != 4 || len(resp.Srl[0][0]) != 3 {
+		t.Errorf("Sdp/Srl not decoded: %v %v", resp.Sdp, resp.Srl)
+	}
+	if top := resp.Con[0].Value[0]; top.Key != "TOP" {
+		t.Errorf("Con root = %q, want TOP", top.Key)
+	}
+}
+
+func TestUnmarshalHanRespMalformed(t *testing.T) {
+	b := []byte(`{"tok/fine": [["a", "b"]], "ner/msra": [[["a", "PERSON", 0, 1], ["b", "LOC", "x", 2]]], "con": [["TOP", "oops"]]}`)
+
+	_, err := UnmarshalHanResp(b)
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("err = %v, want *DecodeError", err)
+	}
+	if de.Task != "con" || de.Sentence != 0 || !reflect.DeepEqual(de.Path, []int{1}) {
+		t.Errorf("err = %v, want con sentence 0 element [1]", err)
+	}
+
+	resp, err := UnmarshalHanRespLenient(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Warnings) != 2 {
+		t.Fatalf("Warnings = %v, want 2", resp.Warnings)
+	}
+	if w := resp.Warnings[1]; w.Task != "ner/msra" || w.Sentence != 0 || w.Path[0] != 1 || w.Position != 2 {
+		t.Errorf("warning = %v, want ner/msra sentence 0 element [1] position 2", w)
+	}
+	if len(resp.NerMsra[0]) != 1 || len(resp.Con) != 1 {
+		t.Errorf("lenient decode kept %v %v", resp.NerMsra, resp.Con)
+	}
+}
+
+func TestUnmarshalHanRespLenientAligned(t *testing.T) {
+	b := []byte(`{"tok/fine": [["a", 1, "c"]], "pos/ctb": [["NN", "VV", "NN"]], "dep": [[[2, "nsubj"], [0, "root"], "bad"]], "srl": [["oops", [["a", "ARG0", 0, 1], ["b", "PRED", 1, 2], ["c", "ARG1", "x", 3]]]]}`)
+	resp, err := UnmarshalHanRespLenient(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Warnings) != 4 {
+		t.Errorf("Warnings = %v, want 4", resp.Warnings)
+	}
+	if want := []string{"a", "", "c"}; !reflect.DeepEqual(resp.TokFine[0], want) {
+		t.Errorf("tok/fine = %q, want %q", resp.TokFine[0], want)
+	}
+	// 依存弧以 Head = -1 占位, 不能当作根
+	if len(resp.Dep[0]) != 3 || resp.Dep[0][2].Head != placeholderHead {
+		t.Errorf("dep = %v, want 3 arcs with a placeholder", resp.Dep[0])
+	}
+	if _, err := resp.DepTrees(); err == nil {
+		t.Error("DepTrees with placeholder arc want error")
+	}
+	var buf bytes.Buffer
+	if err = resp.WriteCoNLLU(&buf, ""); err != nil || !strings.Contains(buf.String(), "3\tc\t_\t_\tNN\t_\t_\t_\t_\t_\n") {
+		t.Errorf("WriteCoNLLU = %q, %v, want placeholder arc as _", buf.String(), err)
+	}
+	// 区间元素直接跳过
+	if len(resp.Srl[0]) != 1 || len(resp.Srl[0][0]) != 2 {
+		t.Errorf("srl = %v, want 1 frame with 2 tuples", resp.Srl[0])
+	}
+	if _, err := NewDocument(resp); err != nil {
+		t.Errorf("NewDocument: %v", err)
+	}
+}
+
+func TestHanRespJSONRoundTrip(t *testing.T) {
+	var resp HanResp
+	if err := json.Unmarshal([]byte(sampleResp), &resp); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got, want interface{}
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(sampleResp), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip:\n got %s\nwant %s", b, sampleResp)
+	}
+
+	var ner []NerTuple
+	if err = json.Unmarshal([]byte(`[["晓美焰", "PERSON", 0, "1"]]`), &ner); err == nil {
+		t.Errorf("want error for malformed NerTuple")
+	}
+}
+
+func TestConTupleMarshalHandBuilt(t *testing.T) {
+	tree := ConTuple{Key: "IP", Value: []ConTuple{
+		{Key: "NP", Value: []ConTuple{{Key: "NR", Value: []ConTuple{{Key: "晓美焰"}}}}},
+		{Key: "VP", Value: []ConTuple{{Key: "VV", Value: []ConTuple{{Key: "来到"}}}}},
+	}}
+	b, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `["IP",[["NP",["NR",["晓美焰"]]],["VP",["VV",["来到"]]]]]`
+	if string(b) != want {
+		t.Errorf("marshal = %s, want %s", b, want)
+	}
+
+	var back ConTuple
+	if err = json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if b1, _ := json.Marshal(back); string(b1) != want {
+		t.Errorf("round trip = %s, want %s", b1, want)
+	}
+	if got := NewConTree(back).Words(); !reflect.DeepEqual(got, []string{"晓美焰", "来到"}) {
+		t.Errorf("words = %q", got)
+	}
+}
+
+func TestHanRespMarshalClearedTask(t *testing.T) {
+	resp, err := UnmarshalHanResp([]byte(`{"tok/fine": [["a"]], "pos/ctb": [["NN"]], "custom": [1]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.PosCtb = nil
+	resp.TokFine[0][0] = "b"
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"custom":[1],"tok/fine":[["b"]]}`; string(b) != want {
+		t.Errorf("marshal = %s, want %s", b, want)
+	}
+}
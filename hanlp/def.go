@@ -1,12 +1,17 @@
 package hanlp
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // https://hanlp.hankcs.com/docs/data_format.html
 
 // HanReq hanlp
 type HanReq struct {
 	Text        interface{} `json:"text,omitempty"`
 	Language    string      `json:"language,omitempty"` // (zh,mnt)
-	Tokens      []string    `json:"tokens,omitempty"`
+	Tokens      [][]string  `json:"tokens,omitempty"`
 	Tasks       []string    `json:"tasks,omitempty"`
 	SkipTasks   []string    `json:"skip_tasks,omitempty"`
 	Topk        interface{} `json:"topk,omitempty"`
@@ -19,16 +24,168 @@ type HanReq struct {
 type HanResp struct {
 	TokFine      [][]string     `json:"tok/fine"`
 	TokCoarse    [][]string     `json:"tok/coarse"`
-	PosCtb       [][]string     `json:"pos/ctb"`
-	PosPku       [][]string     `json:"pos/pku"`
-	Pos863       [][]string     `json:"pos/863"`
-	NerPku       [][]NerTuple   `json:"ner/pku"`
-	NerMsra      [][]NerTuple   `json:"ner/msra"`
-	NerOntonotes [][]NerTuple   `json:"ner/ontonotes"`
-	Srl          [][][]SrlTuple `json:"srl"`
-	Dep          [][]DepTuple   `json:"dep"`
-	Sdp          [][][]DepTuple `json:"sdp"`
-	Con          []ConTuple     `json:"con"`
+	PosCtb       [][]string     `json:"pos/ctb"`       //  https://hanlp.hankcs.com/docs/annotations/pos/ctb.html
+	PosPku       [][]string     `json:"pos/pku"`       //  https://hanlp.hankcs.com/docs/annotations/pos/pku.html
+	Pos863       [][]string     `json:"pos/863"`       //  https://hanlp.hankcs.com/docs/annotations/pos/863.html
+	NerPku       [][]NerTuple   `json:"ner/pku"`       //  https://hanlp.hankcs.com/docs/annotations/ner/pku.html
+	NerMsra      [][]NerTuple   `json:"ner/msra"`      //  https://hanlp.hankcs.com/docs/annotations/ner/msra.html
+	NerOntonotes [][]NerTuple   `json:"ner/ontonotes"` //  https://hanlp.hankcs.com/docs/annotations/ner/ontonotes.html
+	Srl          [][][]SrlTuple `json:"srl"`           //  https://hanlp.hankcs.com/docs/annotations/srl/index.html
+	Dep          [][]DepTuple   `json:"dep"`           //  https://hanlp.hankcs.com/docs/annotations/dep/index.html
+	Sdp          [][][]DepTuple `json:"sdp"`           //  https://hanlp.hankcs.com/docs/annotations/sdp/index.html
+	SdpDm        [][][]DepTuple `json:"sdp/dm"`        //  https://hanlp.hankcs.com/docs/annotations/sdp/dm.html
+	SdpPas       [][][]DepTuple `json:"sdp/pas"`       //  https://hanlp.hankcs.com/docs/annotations/sdp/pas.html
+	SdpPsd       [][][]DepTuple `json:"sdp/psd"`       //  https://hanlp.hankcs.com/docs/annotations/sdp/psd.html
+	Con          []ConTuple     `json:"con"`           //  https://hanlp.hankcs.com/docs/annotations/constituency/index.html
+
+	// 多语种(mul) Universal Dependencies 风格, Dep/Sdp/Con/Srl 与中文共用
+	Tok  [][]string   `json:"tok"`
+	Lem  [][]string   `json:"lem"`  // 词元
+	Fea  [][]string   `json:"fea"`  // UD 形态特征, 如 Number=Sing|Person=3
+	Pos  [][]string   `json:"pos"`  // UPOS
+	Xpos [][]string   `json:"xpos"` // 语言相关词性, 服务端返回时才有
+	Ner  [][]NerTuple `json:"ner"`
+
+	Sentences []string `json:"-"` // 文档模式下各句的原文, 由客户端按分词结果在原文中还原
+
+	Warnings []*DecodeError `json:"-"` // 宽松解码时出错的元素
+
+	raw   map[string]json.RawMessage // 所有任务键的原始 json
+	tasks map[string]interface{}     // 自定义解码器的结果
+}
+
+// Tokens 分词结果, 优先细粒度
+func (r *HanResp) Tokens() [][]string {
+	if len(r.TokFine) > 0 {
+		return r.TokFine
+	}
+	if len(r.TokCoarse) > 0 {
+		return r.TokCoarse
+	}
+	return r.Tok
+}
+
+// SdpSchemes 返回了结果的语义依存标注体系(sdp, sdp/dm, sdp/pas, sdp/psd)
+func (r *HanResp) SdpSchemes() []string {
+	var re []string
+	for _, k := range []string{"sdp", "sdp/dm", "sdp/pas", "sdp/psd"} {
+		if len(r.SdpScheme(k)) > 0 {
+			re = append(re, k)
+		}
+	}
+	return re
+}
+
+// SdpScheme 按任务名取语义依存结果
+func (r *HanResp) SdpScheme(name string) [][][]DepTuple {
+	if p := r.sdpColumn(name); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// posColumn 第一个有结果的词性任务
+func (r *HanResp) posColumn() [][]string {
+	for _, k := range []string{"pos/ctb", "pos/pku", "pos/863", "pos"} {
+		if col := *r.column(k); len(col) > 0 {
+			return col
+		}
+	}
+	return nil
+}
+
+// column 分词/词性/词元/特征等字符串任务的字段
+func (r *HanResp) column(name string) *[][]string {
+	switch name {
+	case "tok/fine":
+		return &r.TokFine
+	case "tok/coarse":
+		return &r.TokCoarse
+	case "pos/ctb":
+		return &r.PosCtb
+	case "pos/pku":
+		return &r.PosPku
+	case "pos/863":
+		return &r.Pos863
+	case "tok":
+		return &r.Tok
+	case "lem":
+		return &r.Lem
+	case "fea":
+		return &r.Fea
+	case "pos":
+		return &r.Pos
+	case "xpos":
+		return &r.Xpos
+	}
+	return nil
+}
+
+// nerTasks 命名实体任务键, 按优先级
+var nerTasks = []string{"ner/msra", "ner/pku", "ner/ontonotes", "ner"}
+
+// firstNerColumn 第一个有结果的命名实体任务
+func (r *HanResp) firstNerColumn() [][]NerTuple {
+	for _, k := range nerTasks {
+		if col := *r.nerColumn(k); len(col) > 0 {
+			return col
+		}
+	}
+	return nil
+}
+
+// nerColumn 命名实体任务的字段
+func (r *HanResp) nerColumn(name string) *[][]NerTuple {
+	switch name {
+	case "ner/pku":
+		return &r.NerPku
+	case "ner/msra":
+		return &r.NerMsra
+	case "ner/ontonotes":
+		return &r.NerOntonotes
+	case "ner":
+		return &r.Ner
+	}
+	return nil
+}
+
+// sdpColumn 语义依存任务的字段
+func (r *HanResp) sdpColumn(name string) *[][][]DepTuple {
+	switch name {
+	case "sdp":
+		return &r.Sdp
+	case "sdp/dm":
+		return &r.SdpDm
+	case "sdp/pas":
+		return &r.SdpPas
+	case "sdp/psd":
+		return &r.SdpPsd
+	}
+	return nil
+}
+
+// Features 第 i 句第 j 个词的 UD 形态特征
+func (r *HanResp) Features(i, j int) map[string]string {
+	if i < 0 || i >= len(r.Fea) || j < 0 || j >= len(r.Fea[i]) {
+		return nil
+	}
+	return ParseFeatures(r.Fea[i][j])
+}
+
+// ParseFeatures 解析 UD 形态特征 Number=Sing|Person=3, "_" 表示无
+func ParseFeatures(s string) map[string]string {
+	if s == "" || s == "_" {
+		return nil
+	}
+	re := make(map[string]string)
+	for _, kv := range strings.Split(s, "|") {
+		if i := strings.Index(kv, "="); i >= 0 {
+			re[kv[:i]] = kv[i+1:]
+		} else {
+			re[kv] = ""
+		}
+	}
+	return re
 }
 
 // NerTuple
@@ -58,18 +215,3 @@ type ConTuple struct {
 	Key   string     `json:"key"`
 	Value []ConTuple `json:"value"`
 }
-
-type hanResp struct {
-	TokFine      [][]string        `json:"tok/fine"`
-	TokCoarse    [][]string        `json:"tok/coarse"`
-	PosCtb       [][]string        `json:"pos/ctb"`       //  https://hanlp.hankcs.com/docs/annotations/pos/ctb.html
-	PosPku       [][]string        `json:"pos/pku"`       //  https://hanlp.hankcs.com/docs/annotations/pos/pku.html
-	Pos863       [][]string        `json:"pos/863"`       //  https://hanlp.hankcs.com/docs/annotations/pos/863.html
-	NerPku       [][]interface{}   `json:"ner/pku"`       //  https://hanlp.hankcs.com/docs/annotations/ner/pku.html
-	NerMsra      [][]interface{}   `json:"ner/msra"`      //  https://hanlp.hankcs.com/docs/annotations/ner/msra.html
-	NerOntonotes [][]interface{}   `json:"ner/ontonotes"` //  https://hanlp.hankcs.com/docs/annotations/ner/ontonotes.html
-	Srl          [][][]interface{} `json:"srl"`           //  https://hanlp.hankcs.com/docs/annotations/srl/index.html
-	Dep          [][]interface{}   `json:"dep"`           //  https://hanlp.hankcs.com/docs/annotations/dep/index.html
-	Sdp          [][][]interface{} `json:"sdp"`           //  https://hanlp.hankcs.com/docs/annotations/sdp/index.html
-	Con          []interface{}     `json:"con"`           //
-}
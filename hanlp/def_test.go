@@ -0,0 +1,72 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalMul(t *testing.T) {
+	resp, err := UnmarshalHanResp([]byte(`{
+		"tok": [["She", "runs"]],
+		"lem": [["she", "run"]],
+		"fea": [["Case=Nom|Number=Sing|Person=3", "_"]],
+		"pos": [["PRON", "VERB"]],
+		"xpos": [["PRP", "VBZ"]],
+		"ner": [[["She", "PERSON", 0, 1]]]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(resp.Tokens(), [][]string{{"She", "runs"}}) || len(resp.TokFine) != 0 {
+		t.Errorf("Tokens = %v, TokFine = %v", resp.Tokens(), resp.TokFine)
+	}
+	if resp.Lem[0][1] != "run" || resp.Pos[0][0] != "PRON" || resp.Xpos[0][1] != "VBZ" {
+		t.Errorf("Lem = %v, Pos = %v, Xpos = %v", resp.Lem, resp.Pos, resp.Xpos)
+	}
+	if want := []NerTuple{{"She", "PERSON", 0, 1}}; !reflect.DeepEqual(resp.Ner[0], want) {
+		t.Errorf("Ner = %v", resp.Ner)
+	}
+	if f := resp.Features(0, 0); f["Person"] != "3" || resp.Features(0, 1) != nil || resp.Features(1, 0) != nil {
+		t.Errorf("Features = %v", f)
+	}
+}
+
+func TestParseFeatures(t *testing.T) {
+	cases := map[string]map[string]string{
+		"":                        nil,
+		"_":                       nil,
+		"Number=Sing":             {"Number": "Sing"},
+		"Number=Sing|Person=3":    {"Number": "Sing", "Person": "3"},
+		"Typo|VerbForm=Fin|Mood=": {"Typo": "", "VerbForm": "Fin", "Mood": ""},
+	}
+	for s, want := range cases {
+		if got := ParseFeatures(s); !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseFeatures(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestSdpSchemes(t *testing.T) {
+	resp, err := UnmarshalHanResp([]byte(`{
+		"tok/fine": [["他", "来"]],
+		"sdp/dm": [[[[2, "ARG1"]], [[0, "Root"]]]],
+		"sdp/pas": [[[[2, "verb_ARG1"]], [[0, "Root"]]]],
+		"sdp/psd": [[[[2, "ACT-arg"]], [[0, "Root"]]]]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resp.SdpSchemes(); !reflect.DeepEqual(got, []string{"sdp/dm", "sdp/pas", "sdp/psd"}) {
+		t.Errorf("SdpSchemes = %v", got)
+	}
+	for name, rel := range map[string]string{"sdp/dm": "ARG1", "sdp/pas": "verb_ARG1", "sdp/psd": "ACT-arg"} {
+		if got := resp.SdpScheme(name); len(got) != 1 || got[0][0][0] != (DepTuple{Head: 2, Relation: rel}) {
+			t.Errorf("SdpScheme(%q) = %v", name, got)
+		}
+	}
+	if resp.Sdp != nil || resp.SdpScheme("sdp") != nil || resp.SdpScheme("sdp/xx") != nil {
+		t.Errorf("missing scheme = %v, %v", resp.SdpScheme("sdp"), resp.SdpScheme("sdp/xx"))
+	}
+}
@@ -0,0 +1,261 @@
+package hanlp
+
+import (
+	"fmt"
+)
+
+// DepTree 一句话的依存句法树. HanResp.Dep 的 Head 从 1 开始、0 表示根,
+// DepTree 的方法统一使用从 0 开始的词下标, 根的父节点记为 -1
+type DepTree struct {
+	Tokens   []string // 可为空
+	Arcs     []DepTuple
+	children [][]int
+	roots    []int
+}
+
+// NewDepTree 构造依存树, tokens 可为 nil. 父节点越界或存在环时返回错误
+func NewDepTree(arcs []DepTuple, tokens []string) (*DepTree, error) {
+	if tokens != nil && len(tokens) != len(arcs) {
+		return nil, fmt.Errorf("dep: %d tokens but %d arcs", len(tokens), len(arcs))
+	}
+	if err := ValidateDep(arcs); err != nil {
+		return nil, err
+	}
+
+	t := &DepTree{Tokens: tokens, Arcs: arcs, children: make([][]int, len(arcs))}
+	for i, a := range arcs {
+		if a.Head == 0 {
+			t.roots = append(t.roots, i)
+			continue
+		}
+		t.children[a.Head-1] = append(t.children[a.Head-1], i)
+	}
+	return t, nil
+}
+
+// DepTrees 每句一棵依存树
+func (r *HanResp) DepTrees() ([]*DepTree, error) {
+	toks := r.Tokens()
+	re := make([]*DepTree, len(r.Dep))
+	for i, arcs := range r.Dep {
+		var sent []string
+		if i < len(toks) {
+			sent = toks[i]
+		}
+		t, err := NewDepTree(arcs, sent)
+		if err != nil {
+			return nil, fmt.Errorf("dep: sentence %d: %v", i, err)
+		}
+		re[i] = t
+	}
+	return re, nil
+}
+
+// ValidateDep 检查父节点下标越界、无根节点和环
+func ValidateDep(arcs []DepTuple) error {
+	hasRoot := false
+	for i, a := range arcs {
+		if a.Head == placeholderHead {
+			return fmt.Errorf("dep: token %d has a malformed arc (lenient decoding placeholder)", i+1)
+		}
+		if a.Head < 0 || a.Head > len(arcs) {
+			return fmt.Errorf("dep: token %d head %d out of range", i+1, a.Head)
+		}
+		if a.Head == i+1 {
+			return fmt.Errorf("dep: token %d is its own head", i+1)
+		}
+		if a.Head == 0 {
+			hasRoot = true
+		}
+	}
+	if len(arcs) > 0 && !hasRoot {
+		return fmt.Errorf("dep: no root")
+	}
+
+	// 0 未访问, 1 访问中, 2 可达根
+	state := make([]int, len(arcs))
+	for i := range arcs {
+		var path []int
+		j := i
+		for j >= 0 && state[j] == 0 {
+			state[j] = 1
+			path = append(path, j)
+			j = arcs[j].Head - 1
+		}
+		if j >= 0 && state[j] == 1 {
+			return fmt.Errorf("dep: cycle through token %d", j+1)
+		}
+		for _, k := range path {
+			state[k] = 2
+		}
+	}
+	return nil
+}
+
+// Len 词数
+func (t *DepTree) Len() int {
+	return len(t.Arcs)
+}
+
+// Head 父节点下标, 根为 -1
+func (t *DepTree) Head(i int) int {
+	return t.Arcs[i].Head - 1
+}
+
+// Relation 与父节点的依存关系
+func (t *DepTree) Relation(i int) string {
+	return t.Arcs[i].Relation
+}
+
+// Root 第一个根节点, 空句返回 -1
+func (t *DepTree) Root() int {
+	if len(t.roots) == 0 {
+		return -1
+	}
+	return t.roots[0]
+}
+
+// Roots 全部根节点, 通常只有一个
+func (t *DepTree) Roots() []int {
+	return t.roots
+}
+
+// Children 子节点, 按词序
+func (t *DepTree) Children(i int) []int {
+	return t.children[i]
+}
+
+// Dependents 关系为 rels 之一的子节点, 如 Dependents(v, "nsubj")
+func (t *DepTree) Dependents(i int, rels ...string) []int {
+	var re []int
+	for _, c := range t.children[i] {
+		if containsString(rels, t.Arcs[c].Relation) {
+			re = append(re, c)
+		}
+	}
+	return re
+}
+
+// Filter 关系为 rels 之一的全部词, 如 Filter("nsubj", "dobj")
+func (t *DepTree) Filter(rels ...string) []int {
+	var re []int
+	for i, a := range t.Arcs {
+		if containsString(rels, a.Relation) {
+			re = append(re, i)
+		}
+	}
+	return re
+}
+
+// SubtreeNodes 以 i 为根的子树的全部词(含 i), 按词序
+func (t *DepTree) SubtreeNodes(i int) []int {
+	in := make([]bool, len(t.Arcs))
+	var walk func(j int)
+	walk = func(j int) {
+		in[j] = true
+		for _, c := range t.children[j] {
+			walk(c)
+		}
+	}
+	walk(i)
+
+	var re []int
+	for j, ok := range in {
+		if ok {
+			re = append(re, j)
+		}
+	}
+	return re
+}
+
+// Subtree 以 i 为根的子树覆盖的词区间, 左闭右开. 非投射时子树可能不连续, 返回最小包含区间
+func (t *DepTree) Subtree(i int) (begin, end int) {
+	nodes := t.SubtreeNodes(i)
+	return nodes[0], nodes[len(nodes)-1] + 1
+}
+
+// Ancestors 由近及远的祖先, 不含 i 本身
+func (t *DepTree) Ancestors(i int) []int {
+	var re []int
+	for j := t.Head(i); j >= 0; j = t.Head(j) {
+		re = append(re, j)
+	}
+	return re
+}
+
+// Depth 根节点为 0
+func (t *DepTree) Depth(i int) int {
+	return len(t.Ancestors(i))
+}
+
+// Path i 到 j 的树上路径(含两端), 经过最近公共祖先. 位于不同根之下时返回 nil
+func (t *DepTree) Path(i, j int) []int {
+	up := append([]int{i}, t.Ancestors(i)...)
+	pos := make(map[int]int, len(up))
+	for k, n := range up {
+		pos[n] = k
+	}
+
+	var down []int
+	for n := j; n >= 0; n = t.Head(n) {
+		if k, ok := pos[n]; ok {
+			re := append([]int(nil), up[:k+1]...)
+			for l := len(down) - 1; l >= 0; l-- {
+				re = append(re, down[l])
+			}
+			return re
+		}
+		down = append(down, n)
+	}
+	return nil
+}
+
+// IsProjective 依存弧之间没有交叉
+func (t *DepTree) IsProjective() bool {
+	for i := range t.Arcs {
+		h := t.Head(i)
+		if h < 0 {
+			continue
+		}
+		lo, hi := i, h
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		// 弧内的词必须被弧的父节点支配
+		for k := lo + 1; k < hi; k++ {
+			dominated := false
+			for _, a := range t.Ancestors(k) {
+				if a == h {
+					dominated = true
+					break
+				}
+			}
+			if !dominated {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// HeadWord 词区间 [begin, end) 的中心词: 父节点在区间外的词, 有多个时取最靠近根的. 空区间返回 -1
+func (t *DepTree) HeadWord(begin, end int) int {
+	head := -1
+	for i := begin; i < end; i++ {
+		if h := t.Head(i); h >= begin && h < end {
+			continue
+		}
+		if head < 0 || t.Depth(i) < t.Depth(head) {
+			head = i
+		}
+	}
+	return head
+}
+
+// Text 词区间 [begin, end) 的文本, 未提供 Tokens 时返回空串
+func (t *DepTree) Text(begin, end int) string {
+	if t.Tokens == nil {
+		return ""
+	}
+	return joinTokens(t.Tokens[begin:end])
+}
@@ -0,0 +1,55 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDepTree(t *testing.T) {
+	resp := newSampleResp(t)
+	trees, err := resp.DepTrees()
+	if err != nil || len(trees) != 1 {
+		t.Fatalf("DepTrees = %v, %v", trees, err)
+	}
+	tree := trees[0]
+
+	if tree.Root() != 1 || tree.Head(1) != -1 || tree.Head(0) != 1 {
+		t.Errorf("Root = %d, Head(0) = %d", tree.Root(), tree.Head(0))
+	}
+	if got := tree.Children(1); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("Children(1) = %v", got)
+	}
+	if b, e := tree.Subtree(3); b != 2 || e != 4 || tree.Text(b, e) != "北京立方庭" {
+		t.Errorf("Subtree(3) = [%d, %d)", b, e)
+	}
+	if got := tree.Path(0, 2); !reflect.DeepEqual(got, []int{0, 1, 3, 2}) {
+		t.Errorf("Path(0, 2) = %v", got)
+	}
+	if got := tree.Ancestors(2); !reflect.DeepEqual(got, []int{3, 1}) {
+		t.Errorf("Ancestors(2) = %v", got)
+	}
+	if got := tree.Dependents(1, "nsubj", "dobj"); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("Dependents = %v", got)
+	}
+	if tree.HeadWord(2, 4) != 3 || tree.HeadWord(0, 4) != 1 || tree.HeadWord(1, 1) != -1 {
+		t.Errorf("HeadWord = %d %d", tree.HeadWord(2, 4), tree.HeadWord(0, 4))
+	}
+	if !tree.IsProjective() {
+		t.Error("IsProjective = false")
+	}
+
+	// 0 -> 2 跨过 1, 1 挂在 3 上
+	np, _ := NewDepTree([]DepTuple{{3, "a"}, {4, "b"}, {0, "root"}, {3, "c"}}, nil)
+	if np.IsProjective() {
+		t.Error("crossing arcs: IsProjective = true")
+	}
+	for _, arcs := range [][]DepTuple{
+		{{2, "a"}, {1, "b"}},
+		{{0, "root"}, {3, "a"}, {2, "b"}},
+		{{0, "root"}, {5, "a"}},
+	} {
+		if err := ValidateDep(arcs); err == nil {
+			t.Errorf("ValidateDep(%v) want error", arcs)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package hanlp
+
+import (
+	"fmt"
+)
+
+// Token 句子中的一个词, 按 HanLP 约定 Head 从 1 开始, 0 表示根节点
+type Token struct {
+	Index  int    `json:"index"` // 句内下标, 从 0 开始
+	Form   string `json:"form"`
+	PosCTB string `json:"pos_ctb,omitempty"`
+	PosPKU string `json:"pos_pku,omitempty"`
+	Pos863 string `json:"pos_863,omitempty"`
+	Upos   string `json:"upos,omitempty"` // mul
+	Xpos   string `json:"xpos,omitempty"` // mul
+	Lemma  string `json:"lemma,omitempty"`
+	Feats  string `json:"feats,omitempty"`
+	Head   int    `json:"head"`
+	DepRel string `json:"deprel,omitempty"`
+
+	Sdp []DepTuple `json:"sdp,omitempty"` // 语义依存(sdp), 可有多个父节点
+}
+
+// Sentence 句子及其全部标注
+type Sentence struct {
+	Index    int                     `json:"index"`
+	Text     string                  `json:"text,omitempty"` // 文档模式下的原句
+	Tokens   []Token                 `json:"tokens"`
+	Entities map[string][]NerTuple   `json:"entities,omitempty"` // 按任务键, 如 ner/msra
+	Srl      [][]SrlTuple            `json:"srl,omitempty"`      // 每个谓词一组论元
+	Sdp      map[string][][]DepTuple `json:"sdp,omitempty"`      // 按任务键, 如 sdp/dm
+	Con      *ConTuple               `json:"con,omitempty"`
+}
+
+// Forms 词序列
+func (s *Sentence) Forms() []string {
+	re := make([]string, len(s.Tokens))
+	for i, t := range s.Tokens {
+		re[i] = t.Form
+	}
+	return re
+}
+
+// Document 以句子为中心的 HanResp 视图
+type Document struct {
+	Resp      *HanResp
+	sentences []Sentence
+}
+
+// NewDocument 按句子重组 HanResp, 各任务的句子数或词数不一致时返回错误
+func NewDocument(resp *HanResp) (*Document, error) {
+	toks := resp.Tokens()
+	n := len(toks)
+	if err := resp.checkLengths(); err != nil {
+		return nil, err
+	}
+
+	sents := make([]Sentence, n)
+	for i := range toks {
+		s := Sentence{Index: i, Tokens: make([]Token, len(toks[i]))}
+		if len(resp.Sentences) == n {
+			s.Text = resp.Sentences[i]
+		}
+		for j, form := range toks[i] {
+			t := Token{Index: j, Form: form}
+			t.PosCTB = cell(resp.PosCtb, i, j)
+			t.PosPKU = cell(resp.PosPku, i, j)
+			t.Pos863 = cell(resp.Pos863, i, j)
+			t.Upos = cell(resp.Pos, i, j)
+			t.Xpos = cell(resp.Xpos, i, j)
+			t.Lemma = cell(resp.Lem, i, j)
+			t.Feats = cell(resp.Fea, i, j)
+			if i < len(resp.Dep) {
+				t.Head = resp.Dep[i][j].Head
+				t.DepRel = resp.Dep[i][j].Relation
+			}
+			if i < len(resp.Sdp) {
+				t.Sdp = resp.Sdp[i][j]
+			}
+			s.Tokens[j] = t
+		}
+
+		for _, k := range nerTasks {
+			if ner := *resp.nerColumn(k); i < len(ner) {
+				if s.Entities == nil {
+					s.Entities = make(map[string][]NerTuple)
+				}
+				s.Entities[k] = ner[i]
+			}
+		}
+		if i < len(resp.Srl) {
+			s.Srl = resp.Srl[i]
+		}
+		for _, k := range resp.SdpSchemes() {
+			if s.Sdp == nil {
+				s.Sdp = make(map[string][][]DepTuple)
+			}
+			s.Sdp[k] = resp.SdpScheme(k)[i]
+		}
+		if i < len(resp.Con) {
+			s.Con = &resp.Con[i]
+		}
+		sents[i] = s
+	}
+
+	return &Document{Resp: resp, sentences: sents}, nil
+}
+
+// Sentences 全部句子
+func (d *Document) Sentences() []Sentence {
+	return d.sentences
+}
+
+// Len 句子数
+func (d *Document) Len() int {
+	return len(d.sentences)
+}
+
+// checkLengths 校验各任务与分词结果的句子数、词数, 以及实体与论元的区间
+func (r *HanResp) checkLengths() error {
+	toks := r.Tokens()
+	n := len(toks)
+
+	for _, k := range []string{"pos/ctb", "pos/pku", "pos/863", "pos", "xpos", "lem", "fea"} {
+		col := *r.column(k)
+		if len(col) == 0 {
+			continue
+		}
+		if len(col) != n {
+			return fmt.Errorf("document: %q has %d sentences, tokens have %d", k, len(col), n)
+		}
+		for i := range col {
+			if len(col[i]) != len(toks[i]) {
+				return fmt.Errorf("document: %q sentence %d has %d tags, want %d tokens", k, i, len(col[i]), len(toks[i]))
+			}
+		}
+	}
+
+	if len(r.Dep) > 0 {
+		if len(r.Dep) != n {
+			return fmt.Errorf("document: %q has %d sentences, tokens have %d", "dep", len(r.Dep), n)
+		}
+		for i := range r.Dep {
+			if len(r.Dep[i]) != len(toks[i]) {
+				return fmt.Errorf("document: %q sentence %d has %d arcs, want %d tokens", "dep", i, len(r.Dep[i]), len(toks[i]))
+			}
+		}
+	}
+
+	for _, k := range r.SdpSchemes() {
+		sdp := r.SdpScheme(k)
+		if len(sdp) != n {
+			return fmt.Errorf("document: %q has %d sentences, tokens have %d", k, len(sdp), n)
+		}
+		for i := range sdp {
+			if len(sdp[i]) != len(toks[i]) {
+				return fmt.Errorf("document: %q sentence %d has %d tokens, want %d", k, i, len(sdp[i]), len(toks[i]))
+			}
+		}
+	}
+
+	for _, k := range nerTasks {
+		ner := *r.nerColumn(k)
+		if len(ner) == 0 {
+			continue
+		}
+		if len(ner) != n {
+			return fmt.Errorf("document: %q has %d sentences, tokens have %d", k, len(ner), n)
+		}
+		for i := range ner {
+			for j, e := range ner[i] {
+				if e.Begin < 0 || e.Begin >= e.End || e.End > len(toks[i]) {
+					return fmt.Errorf("document: %q sentence %d entity %d span [%d, %d) out of %d tokens", k, i, j, e.Begin, e.End, len(toks[i]))
+				}
+			}
+		}
+	}
+
+	if len(r.Srl) > 0 {
+		if len(r.Srl) != n {
+			return fmt.Errorf("document: %q has %d sentences, tokens have %d", "srl", len(r.Srl), n)
+		}
+		for i := range r.Srl {
+			for j, frame := range r.Srl[i] {
+				for _, a := range frame {
+					if a.Begin < 0 || a.Begin >= a.End || a.End > len(toks[i]) {
+						return fmt.Errorf("document: %q sentence %d frame %d span [%d, %d) out of %d tokens", "srl", i, j, a.Begin, a.End, len(toks[i]))
+					}
+				}
+			}
+		}
+	}
+
+	if len(r.Con) > 0 && len(r.Con) != n {
+		return fmt.Errorf("document: %q has %d sentences, tokens have %d", "con", len(r.Con), n)
+	}
+	return nil
+}
+
+func cell(col [][]string, i, j int) string {
+	if i < len(col) && j < len(col[i]) {
+		return col[i][j]
+	}
+	return ""
+}
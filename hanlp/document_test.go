@@ -0,0 +1,30 @@
+package hanlp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewDocument(t *testing.T) {
+	resp := newSampleResp(t)
+
+	doc, err := NewDocument(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Len() != 1 {
+		t.Fatalf("Len = %d, want 1", doc.Len())
+	}
+	s := doc.Sentences()[0]
+	if tok := s.Tokens[0]; tok.Form != "晓美焰" || tok.PosCTB != "NR" || tok.Head != 2 || tok.DepRel != "nsubj" {
+		t.Errorf("Tokens[0] = %+v", tok)
+	}
+	if len(s.Entities["ner/msra"]) != 2 || len(s.Srl) != 1 || s.Con == nil {
+		t.Errorf("sentence = %+v", s)
+	}
+
+	resp.PosCtb[0] = resp.PosCtb[0][:3]
+	if _, err = NewDocument(resp); err == nil || !strings.Contains(err.Error(), `"pos/ctb" sentence 0 has 3 tags`) {
+		t.Errorf("err = %v, want pos/ctb length error", err)
+	}
+}
@@ -0,0 +1,171 @@
+package hanlp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT 以 Graphviz DOT 格式输出一个任务的结构, 每句一个 subgraph cluster_i.
+// task 为 dep, sdp(或 sdp/dm, sdp/pas, sdp/psd), con, srl. 词节点标注词和词性, 边标注关系.
+// 渲染: dot -Tpng out.dot -o out.png
+func (r *HanResp) WriteDOT(w io.Writer, task string) error {
+	toks := r.Tokens()
+	pos := r.posColumn()
+
+	var sentence func(bw *bufio.Writer, i int) error
+	var n int
+	switch {
+	case task == "dep":
+		n = len(r.Dep)
+		sentence = func(bw *bufio.Writer, i int) error {
+			if len(r.Dep[i]) != len(toks[i]) {
+				return fmt.Errorf("dot: dep sentence %d has %d arcs, %d tokens", i, len(r.Dep[i]), len(toks[i]))
+			}
+			arcs := make([][]DepTuple, len(r.Dep[i]))
+			for j, a := range r.Dep[i] {
+				arcs[j] = []DepTuple{a}
+			}
+			writeDOTArcs(bw, i, toks[i], sentenceOf(pos, i), arcs)
+			return nil
+		}
+	case r.sdpColumn(task) != nil:
+		sdp := *r.sdpColumn(task)
+		n = len(sdp)
+		sentence = func(bw *bufio.Writer, i int) error {
+			if len(sdp[i]) != len(toks[i]) {
+				return fmt.Errorf("dot: %s sentence %d has %d nodes, %d tokens", task, i, len(sdp[i]), len(toks[i]))
+			}
+			writeDOTArcs(bw, i, toks[i], sentenceOf(pos, i), sdp[i])
+			return nil
+		}
+	case task == "con":
+		n = len(r.Con)
+		sentence = func(bw *bufio.Writer, i int) error {
+			writeDOTCon(bw, i, NewConTree(r.Con[i]))
+			return nil
+		}
+	case task == "srl":
+		frames, err := r.Frames()
+		if err != nil {
+			return err
+		}
+		n = len(frames)
+		sentence = func(bw *bufio.Writer, i int) error {
+			writeDOTFrames(bw, i, frames[i])
+			return nil
+		}
+	default:
+		return fmt.Errorf("dot: unsupported task %q", task)
+	}
+	if task != "con" && task != "srl" && n != len(toks) {
+		return fmt.Errorf("dot: %q has %d sentences, tokens have %d", task, n, len(toks))
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(task))
+	bw.WriteString("  node [shape=box, fontname=\"sans-serif\"];\n  edge [fontname=\"sans-serif\", fontsize=10];\n")
+	for i := 0; i < n; i++ {
+		text := ""
+		if i < len(r.Sentences) {
+			text = r.Sentences[i]
+		} else if i < len(toks) {
+			text = joinTokens(toks[i])
+		}
+		fmt.Fprintf(bw, "  subgraph cluster_%d {\n    label=%s;\n", i, dotQuote(text))
+		if err := sentence(bw, i); err != nil {
+			return err
+		}
+		bw.WriteString("  }\n")
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// writeDOTArcs 依存句法和语义依存: 词节点按词序排成一行, 根节点单独一个
+func writeDOTArcs(bw *bufio.Writer, i int, toks, pos []string, arcs [][]DepTuple) {
+	fmt.Fprintf(bw, "    s%d_root [label=\"ROOT\", shape=plaintext];\n", i)
+	ids := make([]string, len(toks))
+	for j, tok := range toks {
+		ids[j] = fmt.Sprintf("s%d_t%d", i, j)
+		label := tok
+		if j < len(pos) && pos[j] != "" {
+			label += "\n" + pos[j]
+		}
+		fmt.Fprintf(bw, "    %s [label=%s];\n", ids[j], dotQuote(label))
+	}
+	if len(ids) > 0 {
+		fmt.Fprintf(bw, "    { rank=same; %s; }\n", strings.Join(ids, "; "))
+		if len(ids) > 1 {
+			fmt.Fprintf(bw, "    %s [style=invis];\n", strings.Join(ids, " -> "))
+		}
+	}
+	for j, heads := range arcs {
+		for _, a := range heads {
+			from := fmt.Sprintf("s%d_root", i)
+			if a.Head > 0 && a.Head <= len(toks) {
+				from = ids[a.Head-1]
+			} else if a.Head != 0 {
+				continue
+			}
+			fmt.Fprintf(bw, "    %s -> %s [label=%s];\n", from, ids[j], dotQuote(a.Relation))
+		}
+	}
+}
+
+// writeDOTCon 成分句法: 短语节点为椭圆, 词为无边框文本并按词序排成一行
+func writeDOTCon(bw *bufio.Writer, i int, tree *ConTree) {
+	k := 0
+	var leaves []string
+	var walk func(n *ConNode) string
+	walk = func(n *ConNode) string {
+		id := fmt.Sprintf("s%d_n%d", i, k)
+		k++
+		shape := "ellipse"
+		if n.IsLeaf() {
+			shape = "plaintext"
+			leaves = append(leaves, id)
+		}
+		fmt.Fprintf(bw, "    %s [label=%s, shape=%s];\n", id, dotQuote(n.Label), shape)
+		for _, c := range n.Children {
+			fmt.Fprintf(bw, "    %s -> %s [arrowhead=none];\n", id, walk(c))
+		}
+		return id
+	}
+	if tree.Root == nil {
+		return
+	}
+	walk(tree.Root)
+
+	if len(leaves) > 1 {
+		fmt.Fprintf(bw, "    { rank=same; %s; }\n", strings.Join(leaves, "; "))
+		fmt.Fprintf(bw, "    %s [style=invis];\n", strings.Join(leaves, " -> "))
+	}
+}
+
+// writeDOTFrames 语义角色: 每个谓词一个节点, 边指向论元
+func writeDOTFrames(bw *bufio.Writer, i int, frames []Frame) {
+	for j, f := range frames {
+		pred := fmt.Sprintf("s%d_p%d", i, j)
+		fmt.Fprintf(bw, "    %s [label=%s, style=filled, fillcolor=lightgrey];\n", pred, dotQuote(f.Predicate.Text))
+		for k, a := range f.Args {
+			id := fmt.Sprintf("%s_a%d", pred, k)
+			fmt.Fprintf(bw, "    %s [label=%s];\n", id, dotQuote(a.Text))
+			fmt.Fprintf(bw, "    %s -> %s [label=%s];\n", pred, id, dotQuote(a.Role))
+		}
+	}
+}
+
+// dotQuote 双引号字符串, 换行写作 \n
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// sentenceOf 第 i 句, 越界时返回 nil
+func sentenceOf(col [][]string, i int) []string {
+	if i < len(col) {
+		return col[i]
+	}
+	return nil
+}
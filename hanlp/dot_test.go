@@ -0,0 +1,35 @@
+package hanlp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	resp := newSampleResp(t)
+
+	for task, want := range map[string][]string{
+		"dep": {"subgraph cluster_0 {", `label="晓美焰来到北京立方庭";`, `s0_t0 [label="晓美焰\nNR"];`, `s0_root -> s0_t1 [label="root"];`, `s0_t1 -> s0_t0 [label="nsubj"];`},
+		"sdp": {`s0_t1 -> s0_t0 [label="Agt"];`},
+		"con": {`s0_n0 [label="TOP", shape=ellipse];`, `[label="立方庭", shape=plaintext];`, "rank=same;"},
+		"srl": {`s0_p0 [label="来到", style=filled, fillcolor=lightgrey];`, `s0_p0 -> s0_p0_a1 [label="ARG1"];`},
+	} {
+		var buf bytes.Buffer
+		if err := resp.WriteDOT(&buf, task); err != nil {
+			t.Fatalf("WriteDOT(%s): %v", task, err)
+		}
+		out := buf.String()
+		if !strings.HasPrefix(out, "digraph ") || !strings.HasSuffix(out, "}\n") {
+			t.Errorf("WriteDOT(%s) = %s", task, out)
+		}
+		for _, w := range want {
+			if !strings.Contains(out, w) {
+				t.Errorf("WriteDOT(%s) missing %q in\n%s", task, w, out)
+			}
+		}
+	}
+	if err := resp.WriteDOT(&bytes.Buffer{}, "amr"); err == nil {
+		t.Error("unsupported task want error")
+	}
+}
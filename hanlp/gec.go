@@ -0,0 +1,158 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// EditOp 纠错编辑类型
+type EditOp string
+
+const (
+	EditReplace EditOp = "replace" // 替换
+	EditInsert  EditOp = "insert"  // 插入
+	EditDelete  EditOp = "delete"  // 删除
+)
+
+// GecEdit 单处纠错编辑. Begin/End 为原文中的字符(rune)下标, 左闭右开
+type GecEdit struct {
+	Op          EditOp `json:"op"`
+	Begin       int    `json:"begin"`
+	End         int    `json:"end"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+}
+
+// GecResult 文本纠错结果
+type GecResult struct {
+	Text      string    `json:"text"`      // 原文
+	Corrected string    `json:"corrected"` // 纠错后文本
+	Edits     []GecEdit `json:"edits"`     // 字符级编辑列表
+}
+
+// Apply 只应用 accept 返回 true 的编辑, 得到部分采纳后的文本. accept 的 i 为 Edits 中的下标,
+// 编辑按起点排序后应用, 区间越界或相互重叠时返回错误
+func (r GecResult) Apply(accept func(i int, e GecEdit) bool) (string, error) {
+	src := []rune(r.Text)
+	order := make([]int, 0, len(r.Edits))
+	for i, e := range r.Edits {
+		if e.Begin < 0 || e.Begin > e.End || e.End > len(src) {
+			return "", fmt.Errorf("gec: edit %d [%d, %d) out of %d runes", i, e.Begin, e.End, len(src))
+		}
+		if accept(i, e) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := r.Edits[order[a]], r.Edits[order[b]]
+		if ea.Begin != eb.Begin {
+			return ea.Begin < eb.Begin
+		}
+		return ea.End < eb.End
+	})
+
+	var out []rune
+	last := 0
+	for _, i := range order {
+		e := r.Edits[i]
+		if e.Begin < last {
+			return "", fmt.Errorf("gec: edit %d [%d, %d) overlaps a previous edit ending at %d", i, e.Begin, e.End, last)
+		}
+		out = append(out, src[last:e.Begin]...)
+		out = append(out, []rune(e.Replacement)...)
+		last = e.End
+	}
+	out = append(out, src[last:]...)
+	return string(out), nil
+}
+
+// GrammaticalErrorCorrectionObj 文本纠错, 返回与输入对齐的结构化结果
+func (h *hanlp) GrammaticalErrorCorrectionObj(text []string, opts ...Option) ([]GecResult, error) {
+	b, err := h.GrammaticalErrorCorrection(text, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var corrected []string
+	if err = json.Unmarshal([]byte(b), &corrected); err != nil {
+		mylog.Error(err)
+		return nil, err
+	}
+	if len(corrected) != len(text) {
+		return nil, fmt.Errorf("grammatical_error_correction: got %d results for %d inputs", len(corrected), len(text))
+	}
+
+	re := make([]GecResult, len(text))
+	for i := range text {
+		re[i] = GecResult{
+			Text:      text[i],
+			Corrected: corrected[i],
+			Edits:     DiffEdits(text[i], corrected[i]),
+		}
+	}
+	return re, nil
+}
+
+// DiffEdits 计算 src 到 dst 的字符级编辑(基于最长公共子序列)
+func DiffEdits(src, dst string) []GecEdit {
+	a, b := []rune(src), []rune(dst)
+	n, m := len(a), len(b)
+
+	// lcs[i][j] = a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []GecEdit
+	i, j := 0, 0
+	for i < n || j < m {
+		if i < n && j < m && a[i] == b[j] {
+			i++
+			j++
+			continue
+		}
+		// 收集连续的不相等区间
+		bi, bj := i, j
+		for i < n || j < m {
+			if i < n && j < m && a[i] == b[j] {
+				break
+			}
+			if j >= m || (i < n && lcs[i+1][j] >= lcs[i][j+1]) {
+				i++
+			} else {
+				j++
+			}
+		}
+		e := GecEdit{
+			Begin:       bi,
+			End:         i,
+			Original:    string(a[bi:i]),
+			Replacement: string(b[bj:j]),
+		}
+		switch {
+		case bi == i:
+			e.Op = EditInsert
+		case bj == j:
+			e.Op = EditDelete
+		default:
+			e.Op = EditReplace
+		}
+		edits = append(edits, e)
+	}
+	return edits
+}
@@ -0,0 +1,59 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffEdits(t *testing.T) {
+	cases := []struct {
+		src, dst string
+		want     []GecEdit
+	}{
+		{"每个青年都应当有远大的报复。", "每个青年都应当有远大的抱负。",
+			[]GecEdit{{Op: EditReplace, Begin: 11, End: 13, Original: "报复", Replacement: "抱负"}}},
+		{"有的同学对语言很兴趣。", "有的同学对语言很有兴趣。",
+			[]GecEdit{{Op: EditInsert, Begin: 8, End: 8, Original: "", Replacement: "有"}}},
+		{"我我爱北京", "我爱北京",
+			[]GecEdit{{Op: EditDelete, Begin: 1, End: 2, Original: "我", Replacement: ""}}},
+		{"没有错误", "没有错误", nil},
+	}
+
+	for _, c := range cases {
+		got := DiffEdits(c.src, c.dst)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("DiffEdits(%q, %q) = %+v, want %+v", c.src, c.dst, got, c.want)
+		}
+		r := GecResult{Text: c.src, Corrected: c.dst, Edits: got}
+		if s, err := r.Apply(func(int, GecEdit) bool { return true }); err != nil || s != c.dst {
+			t.Errorf("Apply all = %q, %v, want %q", s, err, c.dst)
+		}
+		if s, err := r.Apply(func(int, GecEdit) bool { return false }); err != nil || s != c.src {
+			t.Errorf("Apply none = %q, %v, want %q", s, err, c.src)
+		}
+	}
+}
+
+func TestGecApply(t *testing.T) {
+	r := GecResult{Text: "我我爱北京", Edits: []GecEdit{
+		{Op: EditReplace, Begin: 3, End: 5, Original: "北京", Replacement: "上海"},
+		{Op: EditDelete, Begin: 1, End: 2, Original: "我"},
+	}}
+	if s, err := r.Apply(func(i int, e GecEdit) bool { return true }); err != nil || s != "我爱上海" {
+		t.Errorf("Apply unsorted = %q, %v", s, err)
+	}
+	if s, err := r.Apply(func(i int, e GecEdit) bool { return i == 0 }); err != nil || s != "我我爱上海" {
+		t.Errorf("Apply partial = %q, %v", s, err)
+	}
+
+	for _, edits := range [][]GecEdit{
+		{{Begin: 0, End: 3}, {Begin: 2, End: 4}},
+		{{Begin: 4, End: 9}},
+		{{Begin: 3, End: 2}},
+	} {
+		r := GecResult{Text: "我我爱北京", Edits: edits}
+		if s, err := r.Apply(func(int, GecEdit) bool { return true }); err == nil {
+			t.Errorf("Apply(%v) = %q, want error", edits, s)
+		}
+	}
+}
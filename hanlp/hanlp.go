@@ -1,10 +1,12 @@
 package hanlp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
+	"sync"
 
 	"github.com/imroc/req"
 	"github.com/xxjwxc/public/mylog"
@@ -12,6 +14,9 @@ import (
 
 type hanlp struct {
 	opts Options
+
+	mu   sync.Mutex
+	info map[string]*ServerInfo // Capabilities cache, 按服务端地址
 }
 
 // HanLPClient build client
@@ -78,14 +83,19 @@ func (h *hanlp) Parse(text []string, opts ...Option) (string, error) {
 		f(&options)
 	}
 
-	req := &HanReq{
-		Text:      text,
-		Language:  options.Language, // (zh,mnt)
-		Tasks:     options.Tasks,
-		SkipTasks: options.SkipTasks,
+	if err := h.autoLanguage(text, &options); err != nil {
+		return "", err
+	}
+	if err := h.checkCapabilities(options); err != nil {
+		return "", err
+	}
+
+	req, err := newParseReq(text, options)
+	if err != nil {
+		return "", err
 	}
 
-	return h.Post("/parse", req, getHeader(options))
+	return h.post(options, "/parse", req)
 }
 
 /*
@@ -123,7 +133,7 @@ func (h *hanlp) GrammaticalErrorCorrection(text []string, opts ...Option) (strin
 		Language: options.Language, // (zh,mnt)
 	}
 
-	return h.Post("/grammatical_error_correction", req, getHeader(options))
+	return h.post(options, "/grammatical_error_correction", req)
 }
 
 /*
@@ -164,7 +174,7 @@ func (h *hanlp) KeyphraseExtraction(text string, opts ...Option) (string, error)
 		Topk:     options.Topk,
 	}
 
-	return h.Post("/keyphrase_extraction", req, getHeader(options))
+	return h.post(options, "/keyphrase_extraction", req)
 }
 
 /*
@@ -208,7 +218,7 @@ func (h *hanlp) SemanticTextualSimilarity(text [][]string, opts ...Option) (stri
 		Topk:     options.Topk,
 	}
 
-	return h.Post("/semantic_textual_similarity", req, getHeader(options))
+	return h.post(options, "/semantic_textual_similarity", req)
 }
 
 /*
@@ -243,7 +253,7 @@ func (h *hanlp) TextClassification(text []string, model string, opts ...Option)
 		Model:    model,
 	}
 
-	return h.Post("/text_classification", req, getHeader(options))
+	return h.post(options, "/text_classification", req)
 }
 
 /*
@@ -284,7 +294,7 @@ func (h *hanlp) SentimentAnalysis(text []string, opts ...Option) (string, error)
 		Topk:     false,
 	}
 
-	return h.Post("/sentiment_analysis", req, getHeader(options))
+	return h.post(options, "/sentiment_analysis", req)
 }
 
 /*
@@ -323,7 +333,7 @@ func (h *hanlp) AbstractiveSummarization(text string, opts ...Option) (string, e
 
 	}
 
-	return h.Post("/abstractive_summarization", req, getHeader(options))
+	return h.post(options, "/abstractive_summarization", req)
 }
 
 /*
@@ -372,7 +382,7 @@ func (h *hanlp) ExtractiveSummarization(text string, opts ...Option) (string, er
 		Topk:     options.Topk,
 	}
 
-	return h.Post("/extractive_summarization", req, getHeader(options))
+	return h.post(options, "/extractive_summarization", req)
 }
 
 /*
@@ -403,26 +413,47 @@ Text style transfer aims to change the style of the input text to the target sty
 	    # Output:
 	    '我看见窗外的白云绿林'
 */
-func (h *hanlp) TextStyleTransfer(text []string, style string, opts ...Option) (string, error) {
+func (h *hanlp) TextStyleTransfer(text []string, style Style, opts ...Option) (string, error) {
+	if err := h.validateStyle(style, opts...); err != nil {
+		return "", err
+	}
+	return h.postTextStyleTransfer(text, style, opts...)
+}
+
+// postTextStyleTransfer 不校验风格, 直接请求
+func (h *hanlp) postTextStyleTransfer(text []string, style Style, opts ...Option) (string, error) {
 	options := h.opts
 	for _, f := range opts { // option
 		f(&options)
 	}
 
-	if len(style) == 0 {
-		style = "modern_poetry"
-	}
 	req := &HanReq{
 		Text:        text,
 		Language:    options.Language, // (zh,mnt)
-		TargetStyle: style,
+		TargetStyle: string(style),
 	}
 
-	return h.Post("/text_style_transfer", req, getHeader(options))
+	return h.post(options, "/text_style_transfer", req)
 }
 
 func (h *hanlp) Post(uri string, hreq *HanReq, header http.Header) (string, error) {
-	resp, err := req.Post(h.opts.URL+uri, req.BodyJSON(hreq), header)
+	return h.PostCtx(context.Background(), uri, hreq, header)
+}
+
+// PostCtx post with context (cancel, deadline)
+func (h *hanlp) PostCtx(ctx context.Context, uri string, hreq *HanReq, header http.Header) (string, error) {
+	return h.postURL(ctx, h.opts.URL+uri, hreq, header)
+}
+
+// post 使用单次调用的 URL, 认证, context 和超时
+func (h *hanlp) post(options Options, uri string, hreq *HanReq) (string, error) {
+	ctx, cancel := getContext(options)
+	defer cancel()
+	return h.postURL(ctx, options.URL+uri, hreq, getHeader(options))
+}
+
+func (h *hanlp) postURL(ctx context.Context, url string, hreq *HanReq, header http.Header) (string, error) {
+	resp, err := req.Post(url, req.BodyJSON(hreq), header, ctx)
 	if err != nil {
 		return "", err
 	}
@@ -435,25 +466,28 @@ func (h *hanlp) Post(uri string, hreq *HanReq, header http.Header) (string, erro
 }
 
 func (h *hanlp) PostObj(uri string, hreq *HanReq, header http.Header) (*HanResp, error) {
-	resp, err := req.Post(h.opts.URL+uri, req.BodyJSON(hreq), header)
+	b, err := h.Post(uri, hreq, header)
 	if err != nil {
 		return nil, err
 	}
+	return UnmarshalHanResp([]byte(b))
+}
 
-	b, err := resp.ToBytes()
+// postObj 同 post, options.Lenient: skip malformed elements and collect HanResp.Warnings
+func (h *hanlp) postObj(options Options, uri string, hreq *HanReq) (*HanResp, error) {
+	b, err := h.post(options, uri, hreq)
 	if err != nil {
 		return nil, err
 	}
-
-	if resp.Response().StatusCode >= http.StatusBadRequest {
-		return nil, fmt.Errorf("HttpCode:%d\n%s", resp.Response().StatusCode, resp.String())
-	}
-
-	return UnmarshalHanResp(b)
+	return unmarshalHanResp([]byte(b), options.Lenient)
 }
 
 func (h *hanlp) Get(uri string, header http.Header) (string, error) {
-	resp, err := req.Get(h.opts.URL+uri, header)
+	return h.getCtx(context.Background(), h.opts.URL+uri, header)
+}
+
+func (h *hanlp) getCtx(ctx context.Context, url string, header http.Header) (string, error) {
+	resp, err := req.Get(url, header, ctx)
 	if err != nil {
 		return "", err
 	}
@@ -465,19 +499,22 @@ func (h *hanlp) Get(uri string, header http.Header) (string, error) {
 	return resp.ToString()
 }
 
+// About server info (raw json)
 func (h *hanlp) About(opts ...Option) (string, error) {
 	options := h.opts
 	for _, f := range opts { // option
 		f(&options)
 	}
 
-	b, err := h.Get("/about", getHeader(options))
+	ctx, cancel := getContext(options)
+	defer cancel()
+	b, err := h.getCtx(ctx, options.URL+"/about", getHeader(options))
 	if err != nil {
 		mylog.Error(err)
 		return "", err
 	}
 
-	return string(b), nil
+	return b, nil
 }
 
 // Parse parse object
@@ -487,14 +524,19 @@ func (h *hanlp) ParseObj(text []string, opts ...Option) (*HanResp, error) {
 		f(&options)
 	}
 
-	req := &HanReq{
-		Text:      text,
-		Language:  options.Language, // (zh,mnt)
-		Tasks:     options.Tasks,
-		SkipTasks: options.SkipTasks,
+	if err := h.autoLanguage(text, &options); err != nil {
+		return nil, err
+	}
+	if err := h.checkCapabilities(options); err != nil {
+		return nil, err
+	}
+
+	req, err := newParseReq(text, options)
+	if err != nil {
+		return nil, err
 	}
 
-	return h.PostObj("/parse", req, getHeader(options))
+	return h.postObj(options, "/parse", req)
 }
 
 // ParseAny parse any request parms
@@ -509,13 +551,18 @@ func (h *hanlp) ParseAny(text []string, resp interface{}, opts ...Option) error
 		f(&options)
 	}
 
-	req := &HanReq{
-		Text:      text,
-		Language:  options.Language, // (zh,mnt)
-		Tasks:     options.Tasks,
-		SkipTasks: options.SkipTasks,
+	if err := h.autoLanguage(text, &options); err != nil {
+		return err
+	}
+	if err := h.checkCapabilities(options); err != nil {
+		return err
+	}
+
+	req, err := newParseReq(text, options)
+	if err != nil {
+		return err
 	}
-	b, err := h.Post("/parse", req, getHeader(options))
+	b, err := h.post(options, "/parse", req)
 	if err != nil {
 		return err
 	}
@@ -526,8 +573,10 @@ func (h *hanlp) ParseAny(text []string, resp interface{}, opts ...Option) error
 	case *[]byte:
 		*v = []byte(b)
 	case *HanResp:
-		tmp, e := UnmarshalHanResp([]byte(b))
-		*v, err = *tmp, e
+		var tmp *HanResp
+		if tmp, err = unmarshalHanResp([]byte(b), options.Lenient); err == nil {
+			*v = *tmp
+		}
 	default:
 		err = json.Unmarshal([]byte(b), v)
 	}
@@ -539,164 +588,6 @@ func (h *hanlp) ParseAny(text []string, resp interface{}, opts ...Option) error
 	return nil
 }
 
-// marshal obj
-func UnmarshalHanResp(b []byte) (*HanResp, error) {
-	var hr hanResp
-	err := json.Unmarshal(b, &hr)
-	if err != nil {
-		mylog.Error(err)
-		return nil, err
-	}
-	resp := &HanResp{
-		TokFine:   hr.TokFine,
-		TokCoarse: hr.TokCoarse,
-		PosCtb:    hr.PosCtb,
-		PosPku:    hr.PosPku,
-		Pos863:    hr.Pos863,
-	}
-
-	// ner/pku
-	for _, v := range hr.NerPku {
-		var tmp []NerTuple
-		for _, v1 := range v {
-			switch t := v1.(type) {
-			case []interface{}:
-				{
-					tmp = append(tmp, NerTuple{
-						Entity: t[0].(string),
-						Type:   t[1].(string),
-						Begin:  int(t[2].(float64)),
-						End:    int(t[3].(float64)),
-					})
-				}
-			default:
-				mylog.Error("%v : not unmarshal", t)
-			}
-		}
-		resp.NerPku = append(resp.NerPku, tmp)
-	}
-	// ----------end
-
-	// ner/msra
-	for _, v := range hr.NerMsra {
-		var tmp []NerTuple
-		for _, v1 := range v {
-			switch t := v1.(type) {
-			case []interface{}:
-				{
-					tmp = append(tmp, NerTuple{
-						Entity: t[0].(string),
-						Type:   t[1].(string),
-						Begin:  int(t[2].(float64)),
-						End:    int(t[3].(float64)),
-					})
-				}
-			default:
-				mylog.Error("%v : not unmarshal", t)
-			}
-		}
-		resp.NerMsra = append(resp.NerMsra, tmp)
-	}
-	// ----------end
-
-	// ner/ontonotes
-	for _, v := range hr.NerOntonotes {
-		var tmp []NerTuple
-		for _, v1 := range v {
-			switch t := v1.(type) {
-			case []interface{}:
-				{
-					tmp = append(tmp, NerTuple{
-						Entity: t[0].(string),
-						Type:   t[1].(string),
-						Begin:  int(t[2].(float64)),
-						End:    int(t[3].(float64)),
-					})
-				}
-			default:
-				mylog.Error("%v : not unmarshal", t)
-			}
-		}
-		resp.NerOntonotes = append(resp.NerOntonotes, tmp)
-	}
-	// ----------end
-
-	// srl
-	for _, v := range hr.Srl {
-		var tmp [][]SrlTuple
-		for _, v1 := range v {
-			var tmp1 []SrlTuple
-			for _, v2 := range v1 {
-				switch t := v2.(type) {
-				case []interface{}:
-					{
-						tmp1 = append(tmp1, SrlTuple{
-							ArgPred: t[0].(string),
-							Label:   t[1].(string),
-							Begin:   int(t[2].(float64)),
-							End:     int(t[3].(float64)),
-						})
-					}
-				default:
-					mylog.Error("%v : not unmarshal", t)
-				}
-			}
-			tmp = append(tmp, tmp1)
-		}
-		resp.Srl = append(resp.Srl, tmp)
-	}
-	// -------------end
-
-	// dep
-	for _, v := range hr.Dep {
-		var tmp []DepTuple
-		for _, v1 := range v {
-			switch t := v1.(type) {
-			case []interface{}:
-				{
-					tmp = append(tmp, DepTuple{
-						Head:     int(t[0].(float64)),
-						Relation: t[1].(string),
-					})
-				}
-			default:
-				mylog.Error("%v : not unmarshal", t)
-			}
-		}
-		resp.Dep = append(resp.Dep, tmp)
-	}
-	// ------------end
-	// sdp
-	for _, v := range hr.Sdp {
-		var tmp [][]DepTuple
-		for _, v1 := range v {
-			var tmp1 []DepTuple
-			for _, v2 := range v1 {
-				switch t := v2.(type) {
-				case []interface{}:
-					{
-						tmp1 = append(tmp1, DepTuple{
-							Head:     int(t[0].(float64)),
-							Relation: t[1].(string),
-						})
-					}
-				default:
-					mylog.Error("%v : not unmarshal", t)
-				}
-			}
-			tmp = append(tmp, tmp1)
-		}
-		resp.Sdp = append(resp.Sdp, tmp)
-	}
-	// ------------end
-	// Con
-	resp.Con = dealCon(hr.Con)
-	// ------------end
-
-	// Con          []interface{}
-	return resp, nil
-}
-
 func getHeader(opts Options) http.Header {
 	header := make(http.Header)
 	header.Add("Accept", "application/json")
@@ -707,34 +598,35 @@ func getHeader(opts Options) http.Header {
 	return header
 }
 
-func dealCon(info []interface{}) (re []ConTuple) {
-	if len(info) == 0 {
-		return nil
-	}
-
-	switch t := info[0].(type) {
-	case string:
-		{
-			tmp1 := ConTuple{
-				Key: t,
-			}
-			if len(info) == 2 {
-				tmp1.Value = dealCon(info[1].([]interface{}))
-			}
-			// else { // It doesn't exist in theory
-			// 	fmt.Println(info)
-			// }
-			re = append(re, tmp1)
-		}
-	case []interface{}:
-		{
-			for _, t1 := range info {
-				tmp1 := ConTuple{}
-				tmp1.Value = dealCon(t1.([]interface{}))
-				re = append(re, tmp1)
-			}
-		}
+// getContext 调用方的 context 及 WithTimeout 设置的截止时间
+func getContext(opts Options) (context.Context, context.CancelFunc) {
+	ctx := opts.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout.IsZero() {
+		return context.WithCancel(ctx)
 	}
+	return context.WithDeadline(ctx, opts.Timeout)
+}
 
-	return re
+// newParseReq build /parse request, text and tokens are mutually exclusive
+func newParseReq(text []string, options Options) (*HanReq, error) {
+	if len(text) > 0 && len(options.Tokens) > 0 {
+		return nil, fmt.Errorf("parse: text and tokens are mutually exclusive")
+	}
+	if len(text) == 0 && len(options.Tokens) == 0 {
+		return nil, fmt.Errorf("parse: either text or tokens is required")
+	}
+
+	req := &HanReq{
+		Language:  options.Language, // (zh,mnt)
+		Tokens:    options.Tokens,
+		Tasks:     options.Tasks,
+		SkipTasks: options.SkipTasks,
+	}
+	if len(text) > 0 {
+		req.Text = text
+	}
+	return req, nil
 }
@@ -0,0 +1,129 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// LanguageCode ISO 639-1 语言代码
+type LanguageCode string
+
+const (
+	LangChinese   LanguageCode = "zh"
+	LangEnglish   LanguageCode = "en"
+	LangJapanese  LanguageCode = "ja"
+	LangMongolian LanguageCode = "mn"
+)
+
+// ServerLanguage 对应 /parse 的 language 参数: 中文使用 zh, 蒙古文使用 mnt, 其余使用多语种 mul
+func (l LanguageCode) ServerLanguage() string {
+	switch l {
+	case LangChinese:
+		return "zh"
+	case LangMongolian:
+		return "mnt"
+	}
+	return "mul"
+}
+
+// LanguageGuess 语种识别结果, Prob 仅在 WithProb() 时有值
+type LanguageGuess struct {
+	Language LanguageCode `json:"language"`
+	Prob     float64      `json:"prob,omitempty"`
+}
+
+// UnmarshalJSON 兼容 "zh" 与 ["zh", 0.99] 两种格式
+func (g *LanguageGuess) UnmarshalJSON(b []byte) error {
+	var code string
+	if err := json.Unmarshal(b, &code); err == nil {
+		*g = LanguageGuess{Language: LanguageCode(code)}
+		return nil
+	}
+
+	var t []interface{}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	if len(t) != 2 {
+		return fmt.Errorf("language identification: want [language, prob], got %s", b)
+	}
+	code, ok1 := t[0].(string)
+	prob, ok2 := t[1].(float64)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("language identification: want [language, prob], got %s", b)
+	}
+	*g = LanguageGuess{Language: LanguageCode(code), Prob: prob}
+	return nil
+}
+
+/*
+语种识别
+Identify the language of a given text.
+
+	Args:
+	    text: A document or a list of documents.
+	    topk: ``True`` or ``int`` to return the top-k languages.
+	    prob: Return also probabilities.
+
+	Returns:
+	    Identified language in `ISO 639-1 codes`_.
+
+	Examples::
+
+	    HanLP.language_identification(
+	    'In 2021, HanLPv2.1 delivers state-of-the-art multilingual NLP techniques to production environment.')
+	    'en'
+	    lang, prob = HanLP.language_identification(
+	    '2021年、HanLPv2.1は次世代の最先端多言語NLP技術を本番環境に導入します。', prob=True)
+	    ('ja', 0.9976244568824768)
+*/
+func (h *hanlp) LanguageIdentification(text []string, opts ...Option) ([]LanguageGuess, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	req := &HanReq{
+		Text: text,
+		Prob: options.Prob,
+	}
+
+	b, err := h.post(options, "/language_identification", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var re []LanguageGuess
+	if err = json.Unmarshal([]byte(b), &re); err != nil {
+		mylog.Error(err)
+		return nil, err
+	}
+	if len(re) != len(text) {
+		return nil, fmt.Errorf("language_identification: got %d results for %d inputs", len(re), len(text))
+	}
+	return re, nil
+}
+
+// autoLanguage WithAutoLanguage 时先识别语种: 全部为中文时使用 zh, 全部为蒙古文时使用 mnt, 否则使用 mul
+func (h *hanlp) autoLanguage(text []string, options *Options) error {
+	if !options.AutoLanguage || len(text) == 0 {
+		return nil
+	}
+
+	guesses, err := h.LanguageIdentification(text, withOptions(*options))
+	if err != nil {
+		return err
+	}
+
+	language := guesses[0].Language.ServerLanguage()
+	for _, g := range guesses[1:] {
+		if g.Language.ServerLanguage() != language {
+			language = "mul"
+			break
+		}
+	}
+	options.Language = language
+	return nil
+}
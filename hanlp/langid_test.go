@@ -0,0 +1,69 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLanguageGuessJSON(t *testing.T) {
+	var got []LanguageGuess
+	if err := json.Unmarshal([]byte(`["zh", ["ja", 0.99]]`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != (LanguageGuess{Language: LangChinese}) || got[1] != (LanguageGuess{Language: LangJapanese, Prob: 0.99}) {
+		t.Errorf("guesses = %+v", got)
+	}
+	for _, bad := range []string{`["zh"]`, `[0.99, "zh"]`, `{"language": 1}`} {
+		var g LanguageGuess
+		if err := json.Unmarshal([]byte(bad), &g); err == nil {
+			t.Errorf("Unmarshal(%s) want error", bad)
+		}
+	}
+}
+
+func TestAutoLanguage(t *testing.T) {
+	// 服务端按输入文本返回预置的语种
+	langs := map[string]string{"中文": "zh", "汉语": "zh", "English": "en", "日本語です": "ja", "Монгол": "mn"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic key" {
+			t.Errorf("auth %q", r.Header.Get("Authorization"))
+		}
+		var req HanReq
+		json.NewDecoder(r.Body).Decode(&req)
+		var re []string
+		for _, s := range req.Text.([]interface{}) {
+			re = append(re, langs[s.(string)])
+		}
+		json.NewEncoder(w).Encode(re)
+	}))
+	defer srv.Close()
+
+	// 识别请求使用调用方合并后的选项(URL, 认证等)
+	h := HanLPClient(WithURL("http://127.0.0.1:0"))
+	for _, c := range []struct {
+		text []string
+		want string
+	}{
+		{[]string{"中文", "汉语"}, "zh"},
+		{[]string{"中文", "English"}, "mul"}, // 任一非中文即切换到 mul
+		{[]string{"English", "中文"}, "mul"},
+		{[]string{"日本語です"}, "mul"},
+		{[]string{"Монгол"}, "mnt"},
+		{[]string{"Монгол", "中文"}, "mul"},
+	} {
+		options := Options{URL: srv.URL, Auth: "key", AutoLanguage: true, Language: "zh"}
+		if err := h.autoLanguage(c.text, &options); err != nil {
+			t.Fatal(err)
+		}
+		if options.Language != c.want {
+			t.Errorf("autoLanguage(%v) = %q, want %q", c.text, options.Language, c.want)
+		}
+	}
+
+	options := Options{Language: "zh"}
+	if err := HanLPClient(WithURL("http://127.0.0.1:0")).autoLanguage([]string{"English"}, &options); err != nil || options.Language != "zh" {
+		t.Errorf("AutoLanguage off = %q, %v", options.Language, err)
+	}
+}
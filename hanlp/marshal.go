@@ -0,0 +1,147 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HanLP 线上格式: 元组以 json 数组表示
+
+// UnmarshalJSON [entity, type, begin, end]
+func (t *NerTuple) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	re, pos, err := nerTupleOf(v)
+	if err != nil {
+		return tupleError("ner", pos, err)
+	}
+	*t = re
+	return nil
+}
+
+// MarshalJSON [entity, type, begin, end]
+func (t NerTuple) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{t.Entity, t.Type, t.Begin, t.End})
+}
+
+// UnmarshalJSON [arg/pred, label, begin, end]
+func (t *SrlTuple) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	re, pos, err := srlTupleOf(v)
+	if err != nil {
+		return tupleError("srl", pos, err)
+	}
+	*t = re
+	return nil
+}
+
+// MarshalJSON [arg/pred, label, begin, end]
+func (t SrlTuple) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{t.ArgPred, t.Label, t.Begin, t.End})
+}
+
+// UnmarshalJSON [head, relation]
+func (t *DepTuple) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	re, pos, err := depTupleOf(v)
+	if err != nil {
+		return tupleError("dep", pos, err)
+	}
+	*t = re
+	return nil
+}
+
+// MarshalJSON [head, relation]
+func (t DepTuple) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{t.Head, t.Relation})
+}
+
+// UnmarshalJSON 一棵句法树 [label, [children...]], 解码为无标签的包装节点, 与 HanResp.Con 的元素一致
+func (t *ConTuple) UnmarshalJSON(b []byte) error {
+	var list []interface{}
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	value, path, err := dealCon(list, nil)
+	if err != nil {
+		return fmt.Errorf("con: element %v: %v", path, err)
+	}
+	*t = ConTuple{Value: value}
+	return nil
+}
+
+// MarshalJSON UnmarshalJSON 的逆过程, 有标签的节点输出为 [label, [children...]]
+func (t ConTuple) MarshalJSON() ([]byte, error) {
+	if t.Key == "" {
+		return json.Marshal(conToJSON(t.Value))
+	}
+	return json.Marshal(conNodeToJSON(t))
+}
+
+// conToJSON dealCon 的逆过程. 唯一的有标签元素即列表本身(如叶子), 否则每个元素输出为一棵子树,
+// 无标签的包装节点展开, 未包装的有标签节点(手工构造的树)直接输出
+func conToJSON(info []ConTuple) []interface{} {
+	if len(info) == 1 && info[0].Key != "" {
+		return conNodeToJSON(info[0])
+	}
+
+	re := make([]interface{}, 0, len(info))
+	for _, v := range info {
+		if v.Key != "" {
+			re = append(re, conNodeToJSON(v))
+		} else {
+			re = append(re, conToJSON(v.Value))
+		}
+	}
+	return re
+}
+
+// conNodeToJSON [label] 或 [label, [children...]]
+func conNodeToJSON(t ConTuple) []interface{} {
+	re := []interface{}{t.Key}
+	if t.Value != nil {
+		re = append(re, conToJSON(t.Value))
+	}
+	return re
+}
+
+func tupleError(task string, pos int, err error) error {
+	if pos < 0 {
+		return fmt.Errorf("%s: %v", task, err)
+	}
+	return fmt.Errorf("%s: position %d: %v", task, pos, err)
+}
+
+// UnmarshalJSON 同 UnmarshalHanResp
+func (r *HanResp) UnmarshalJSON(b []byte) error {
+	resp, err := unmarshalHanResp(b, false)
+	if err != nil {
+		return err
+	}
+	*r = *resp
+	return nil
+}
+
+// MarshalJSON 输出 HanLP 线上格式: 内置任务总是取自类型化字段, 只输出有结果的; 其它任务键原样保留
+func (r HanResp) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(r.raw))
+	for k, v := range r.raw {
+		if _, _, known := r.builtin(k); !known {
+			out[k] = v
+		}
+	}
+	for _, name := range builtinTasks {
+		if v, n, _ := r.builtin(name); n > 0 {
+			out[name] = v
+		}
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,203 @@
+package hanlp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TagScheme 序列标注体系
+type TagScheme int
+
+const (
+	BIO   TagScheme = iota // B-X I-X O
+	BIOES                  // B-X I-X E-X S-X O
+)
+
+// ResolveOverlaps 去除重叠或嵌套的实体: 按起点升序、长度降序、类型排序后贪心保留, 即外层和靠前的实体优先
+func ResolveOverlaps(spans []NerTuple) (kept, dropped []NerTuple) {
+	sorted := append([]NerTuple(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Begin != b.Begin {
+			return a.Begin < b.Begin
+		}
+		if a.End != b.End {
+			return a.End > b.End
+		}
+		return a.Type < b.Type
+	})
+
+	end := -1
+	for _, s := range sorted {
+		if s.Begin < end {
+			dropped = append(dropped, s)
+			continue
+		}
+		kept = append(kept, s)
+		end = s.End
+	}
+	return kept, dropped
+}
+
+// SpansToTags 将词下标区间的实体转换为 n 个词的标签序列, 重叠实体按 ResolveOverlaps 处理
+func SpansToTags(spans []NerTuple, n int, scheme TagScheme) ([]string, error) {
+	tags := make([]string, n)
+	for i := range tags {
+		tags[i] = "O"
+	}
+
+	kept, _ := ResolveOverlaps(spans)
+	for _, s := range kept {
+		if s.Begin < 0 || s.Begin >= s.End || s.End > n {
+			return nil, fmt.Errorf("ner: span %v out of %d tokens", s, n)
+		}
+		for i := s.Begin; i < s.End; i++ {
+			tags[i] = "I-" + s.Type
+		}
+		tags[s.Begin] = "B-" + s.Type
+		if scheme == BIOES {
+			if s.End-s.Begin == 1 {
+				tags[s.Begin] = "S-" + s.Type
+			} else {
+				tags[s.End-1] = "E-" + s.Type
+			}
+		}
+	}
+	return tags, nil
+}
+
+// TagsToSpans 将 BIO/BIOES 标签序列还原为实体, 实体文本由 tokens 拼接. 孤立的 I-/E- 视为实体开始
+func TagsToSpans(tokens, tags []string) ([]NerTuple, error) {
+	if len(tokens) != len(tags) {
+		return nil, fmt.Errorf("ner: %d tokens but %d tags", len(tokens), len(tags))
+	}
+
+	var re []NerTuple
+	begin, typ := -1, ""
+	closeSpan := func(end int) {
+		if begin >= 0 {
+			re = append(re, NerTuple{Entity: joinTokens(tokens[begin:end]), Type: typ, Begin: begin, End: end})
+		}
+		begin, typ = -1, ""
+	}
+
+	for i, tag := range tags {
+		if tag == "O" {
+			closeSpan(i)
+			continue
+		}
+		if len(tag) < 3 || tag[1] != '-' {
+			return nil, fmt.Errorf("ner: bad tag %q at %d", tag, i)
+		}
+		prefix, t := tag[0], tag[2:]
+		switch prefix {
+		case 'B', 'S':
+			closeSpan(i)
+			begin, typ = i, t
+		case 'I', 'M', 'E':
+			if begin < 0 || typ != t {
+				closeSpan(i)
+				begin, typ = i, t
+			}
+		default:
+			return nil, fmt.Errorf("ner: bad tag %q at %d", tag, i)
+		}
+		if prefix == 'S' || prefix == 'E' {
+			closeSpan(i + 1)
+		}
+	}
+	closeSpan(len(tags))
+	return re, nil
+}
+
+// WriteCoNLL2003 以 CoNLL-2003 四列格式(词 词性 组块 实体标签)输出 ner 任务(如 ner/msra)的结果.
+// 词性取第一个有结果的词性任务, 组块列为 "_"
+func (r *HanResp) WriteCoNLL2003(w io.Writer, ner string, scheme TagScheme) error {
+	p := r.nerColumn(ner)
+	if p == nil {
+		return fmt.Errorf("conll2003: unknown ner task %q", ner)
+	}
+	pos := r.posColumn()
+
+	toks := r.Tokens()
+	if len(*p) != len(toks) {
+		return fmt.Errorf("conll2003: %q has %d sentences, tokens have %d", ner, len(*p), len(toks))
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("-DOCSTART- -X- -X- O\n\n")
+	for i, sent := range toks {
+		tags, err := SpansToTags((*p)[i], len(sent), scheme)
+		if err != nil {
+			return fmt.Errorf("conll2003: sentence %d: %v", i, err)
+		}
+		for j, tok := range sent {
+			fmt.Fprintf(bw, "%s %s _ %s\n", conll2003Field(tok), conll2003Field(cell(pos, i, j)), tags[j])
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// ReadCoNLL2003 读取 CoNLL 列格式: 首列为词, 末列为 BIO/BIOES 标签, 实体写入 ner 任务(如 ner/msra)
+func ReadCoNLL2003(rd io.Reader, ner string) (*HanResp, error) {
+	resp := &HanResp{}
+	p := resp.nerColumn(ner)
+	if p == nil {
+		return nil, fmt.Errorf("conll2003: unknown ner task %q", ner)
+	}
+
+	var toks, tags []string
+	flush := func() error {
+		if len(toks) == 0 {
+			return nil
+		}
+		spans, err := TagsToSpans(toks, tags)
+		if err != nil {
+			return fmt.Errorf("conll2003: sentence %d: %v", len(resp.TokFine), err)
+		}
+		resp.TokFine = append(resp.TokFine, toks)
+		*p = append(*p, spans)
+		toks, tags = nil, nil
+		return nil
+	}
+
+	sc := bufio.NewScanner(rd)
+	line := 0
+	for sc.Scan() {
+		line++
+		cols := strings.Fields(sc.Text())
+		if len(cols) == 0 {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if cols[0] == "-DOCSTART-" {
+			continue
+		}
+		if len(cols) < 2 {
+			return nil, fmt.Errorf("conll2003: line %d: want at least 2 columns, got %d", line, len(cols))
+		}
+		toks = append(toks, cols[0])
+		tags = append(tags, cols[len(cols)-1])
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// conll2003Field 列以空白分隔, 空值写作 "_"
+func conll2003Field(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.Join(strings.Fields(s), "_")
+}
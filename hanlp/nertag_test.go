@@ -0,0 +1,54 @@
+package hanlp
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSpansToTags(t *testing.T) {
+	spans := []NerTuple{
+		{"立方庭", "ORG", 3, 4},
+		{"北京立方庭", "LOCATION", 2, 4}, // 外层实体优先
+		{"晓美焰", "PERSON", 0, 1},
+	}
+
+	bio, err := SpansToTags(spans, 5, BIO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"B-PERSON", "O", "B-LOCATION", "I-LOCATION", "O"}; !reflect.DeepEqual(bio, want) {
+		t.Errorf("BIO = %v, want %v", bio, want)
+	}
+	bioes, _ := SpansToTags(spans, 5, BIOES)
+	if want := []string{"S-PERSON", "O", "B-LOCATION", "E-LOCATION", "O"}; !reflect.DeepEqual(bioes, want) {
+		t.Errorf("BIOES = %v, want %v", bioes, want)
+	}
+
+	toks := []string{"晓美焰", "来到", "北京", "立方庭", "。"}
+	for _, tags := range [][]string{bio, bioes} {
+		got, err := TagsToSpans(toks, tags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []NerTuple{{"晓美焰", "PERSON", 0, 1}, {"北京立方庭", "LOCATION", 2, 4}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("TagsToSpans(%v) = %v, want %v", tags, got, want)
+		}
+	}
+}
+
+func TestCoNLL2003RoundTrip(t *testing.T) {
+	resp := newSampleResp(t)
+
+	var buf bytes.Buffer
+	if err := resp.WriteCoNLL2003(&buf, "ner/msra", BIOES); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadCoNLL2003(&buf, "ner/msra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.TokFine, resp.TokFine) || !reflect.DeepEqual(got.NerMsra, resp.NerMsra) {
+		t.Errorf("ReadCoNLL2003 = %v %v, want %v %v", got.TokFine, got.NerMsra, resp.TokFine, resp.NerMsra)
+	}
+}
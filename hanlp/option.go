@@ -1,20 +1,29 @@
 package hanlp
 
 import (
+	"context"
 	"time"
 )
 
 // Options opts define
 type Options struct {
-	URL       string
-	Auth      string
-	Topk      int
-	Language  string
-	Timeout   time.Time
-	Tasks     []string
-	SkipTasks []string
-	OutPut    interface{}
-	Tokens    []string
+	URL        string
+	Auth       string
+	Topk       int
+	Language   string
+	Timeout    time.Time
+	Tasks      []string
+	SkipTasks  []string
+	OutPut     interface{}
+	Ctx        context.Context
+	Tokens     [][]string
+	BatchSize  int
+	BatchRunes int
+
+	CheckCapabilities bool
+	AutoLanguage      bool
+	Prob              bool
+	Lenient           bool
 }
 
 // Option opts list func
@@ -54,6 +63,20 @@ func WithTimeout(timeout time.Time) Option {
 	}
 }
 
+// WithContext set context (cancel, deadline) for requests
+func WithContext(ctx context.Context) Option {
+	return func(o *Options) {
+		o.Ctx = ctx
+	}
+}
+
+// withOptions 整体替换, 用于把已合并的 Options 传给内部调用
+func withOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
 // WithTasks set tasks list("tok","ud","ner","srl","sdp/dm","sdp/pas","sdp/psd","con")
 func WithTasks(tasks ...string) Option {
 	return func(o *Options) {
@@ -85,8 +108,51 @@ func WithSkipTasks(skipTasks ...string) Option {
 	}
 }
 
-func WithTokens(tokens ...string) Option {
+// WithTokens set pre-tokenized sentences, tokenization is skipped on server. Pass nil text to Parse/ParseObj/ParseAny
+func WithTokens(tokens ...[]string) Option {
 	return func(o *Options) {
 		o.Tokens = append(o.Tokens, tokens...)
 	}
 }
+
+// WithBatchSize set the max number of sentences per request for batched calls
+func WithBatchSize(size int) Option {
+	return func(o *Options) {
+		o.BatchSize = size
+	}
+}
+
+// WithBatchRunes set the max number of characters per request for batched calls
+func WithBatchRunes(runes int) Option {
+	return func(o *Options) {
+		o.BatchRunes = runes
+	}
+}
+
+// WithCapabilityCheck reject tasks or languages not advertised by server /about before sending
+func WithCapabilityCheck() Option {
+	return func(o *Options) {
+		o.CheckCapabilities = true
+	}
+}
+
+// WithAutoLanguage identify the language of input text first and pick the matching language
+func WithAutoLanguage() Option {
+	return func(o *Options) {
+		o.AutoLanguage = true
+	}
+}
+
+// WithProb return also probabilities
+func WithProb() Option {
+	return func(o *Options) {
+		o.Prob = true
+	}
+}
+
+// WithLenientDecode skip malformed elements in response and collect them in HanResp.Warnings instead of failing
+func WithLenientDecode() Option {
+	return func(o *Options) {
+		o.Lenient = true
+	}
+}
@@ -0,0 +1,62 @@
+package hanlp
+
+// ParseDocument 解析整篇文档, 由服务端分句
+func (h *hanlp) ParseDocument(text string, opts ...Option) (string, error) {
+	options, req, err := h.newDocumentReq(text, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return h.post(options, "/parse", req)
+}
+
+// ParseDocumentObj 解析整篇文档. 服务端不返回句子文本, 结果的 Sentences 由客户端按每句的分词结果
+// 在原文中截取还原, 与服务端的分句一一对应, 句间空白不计入句子
+func (h *hanlp) ParseDocumentObj(text string, opts ...Option) (*HanResp, error) {
+	options, req, err := h.newDocumentReq(text, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.postObj(options, "/parse", req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Sentences = splitSentences(text, resp.Tokens())
+	return resp, nil
+}
+
+func (h *hanlp) newDocumentReq(text string, opts ...Option) (Options, *HanReq, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	if err := h.autoLanguage([]string{text}, &options); err != nil {
+		return options, nil, err
+	}
+	if err := h.checkCapabilities(options); err != nil {
+		return options, nil, err
+	}
+
+	req, err := newParseReq([]string{text}, options)
+	if err != nil {
+		return options, nil, err
+	}
+	req.Text = text // 单个字符串即文档模式
+	return options, req, nil
+}
+
+// splitSentences 根据每句的分词结果在原文中还原句子: 每句为首词起点到末词终点的原文片段, 空句为 ""
+func splitSentences(text string, tokens [][]string) []string {
+	offsets := NewAligner(text).alignDocument(tokens)
+	src := []rune(text)
+
+	re := make([]string, len(tokens))
+	for i, v := range offsets {
+		if len(v) > 0 {
+			re[i] = string(src[v[0].Begin:v[len(v)-1].End])
+		}
+	}
+	return re
+}
@@ -0,0 +1,26 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	cases := []struct {
+		text   string
+		tokens [][]string
+		want   []string
+	}{
+		{"商品和服务。阿婆主来到北京立方庭。", [][]string{{"商品", "和", "服务", "。"}, {"阿婆主", "来到", "北京", "立方庭", "。"}},
+			[]string{"商品和服务。", "阿婆主来到北京立方庭。"}},
+		{"Hello world.  How are you?", [][]string{{"Hello", "world", "."}, {"How", "are", "you", "?"}},
+			[]string{"Hello world.", "How are you?"}}, // 句间空白不计入
+		{"ＨａｎＬＰ好。", [][]string{{"HanLP", "好", "。"}}, []string{"ＨａｎＬＰ好。"}}, // 取原文而非分词结果
+		{"你好", [][]string{{"你好"}, {}}, []string{"你好", ""}},
+	}
+	for _, c := range cases {
+		if got := splitSentences(c.text, c.tokens); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("splitSentences(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package hanlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewParseReq(t *testing.T) {
+	options := Options{Language: "zh"}
+	WithTokens([]string{"商品", "和", "服务"}, []string{"你好"})(&options)
+	WithTasks("pos/ctb")(&options)
+
+	req, err := newParseReq(nil, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(req)
+	if want := `{"language":"zh","tokens":[["商品","和","服务"],["你好"]],"tasks":["pos/ctb"]}`; string(b) != want {
+		t.Errorf("request = %s, want %s", b, want)
+	}
+
+	if _, err := newParseReq([]string{"商品和服务"}, options); err == nil {
+		t.Error("text and tokens together want error")
+	}
+	if _, err := newParseReq(nil, Options{}); err == nil {
+		t.Error("neither text nor tokens want error")
+	}
+	if req, err := newParseReq([]string{"商品和服务"}, Options{}); err != nil || req.Tokens != nil {
+		t.Errorf("text only = %+v, %v", req, err)
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tok/fine": [["a"]]}`))
+	}))
+	defer srv.Close()
+
+	h := HanLPClient(WithURL(srv.URL))
+	if _, err := h.ParseObj([]string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.ParseObj([]string{"a"}, WithContext(ctx)); err == nil {
+		t.Error("canceled context want error")
+	}
+}
@@ -0,0 +1,311 @@
+package hanlp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 仿 HanLP Document.pretty_print 的终端输出, 每句一张表:
+//
+//	Dep Tree Tok    Relation PoS NER Type     PA1
+//	──────── ───    ──────── ─── ────────     ───
+//	  ┌─►    晓美焰 nsubj    NR  ───►PERSON   ───►ARG0
+//	┌─┴──    来到   root     VV               ╟──►PRED
+//	│ ┌─►    北京   nn       NR  ◄─┐          ◄─┐
+//	└─┴─►    立方庭 dobj     NR  ◄─┴►LOCATION ◄─┴►ARG1
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+)
+
+var ansiPalette = []string{"\x1b[31m", "\x1b[32m", "\x1b[33m", "\x1b[34m", "\x1b[35m", "\x1b[36m"}
+
+// PrettyPrint 以对齐的列输出分词、依存句法、词性、命名实体和语义角色, color 为 true 时使用 ANSI 颜色.
+// 词性和命名实体取第一个有结果的任务
+func (r *HanResp) PrettyPrint(w io.Writer, color bool) error {
+	pos, ner := r.posColumn(), r.firstNerColumn()
+
+	bw := bufio.NewWriter(w)
+	for i, sent := range r.Tokens() {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		var cols []prettyColumn
+		if i < len(r.Dep) && len(r.Dep[i]) == len(sent) {
+			if arcs := depArcRows(r.Dep[i]); arcs != nil {
+				cols = append(cols, prettyColumn{header: "Dep Tree", cells: arcs, style: ansiDim})
+			}
+		}
+		cols = append(cols, prettyColumn{header: "Tok", cells: sent})
+		if i < len(r.Dep) && len(r.Dep[i]) == len(sent) {
+			rels := make([]string, len(sent))
+			for j, a := range r.Dep[i] {
+				rels[j] = a.Relation
+			}
+			cols = append(cols, prettyColumn{header: "Relation", cells: rels})
+		}
+		if i < len(pos) && len(pos[i]) == len(sent) {
+			cols = append(cols, prettyColumn{header: "PoS", cells: pos[i]})
+		}
+		if i < len(ner) {
+			spans := make([]labeledSpan, len(ner[i]))
+			for j, e := range ner[i] {
+				spans[j] = labeledSpan{e.Type, e.Begin, e.End}
+			}
+			cols = append(cols, prettyColumn{header: "NER Type", cells: bracketRows(spans, len(sent), ""), labels: true})
+		}
+		if i < len(r.Srl) {
+			for j, frame := range r.Srl[i] {
+				spans := make([]labeledSpan, len(frame))
+				for k, a := range frame {
+					spans[k] = labeledSpan{a.Label, a.Begin, a.End}
+				}
+				cols = append(cols, prettyColumn{header: fmt.Sprintf("PA%d", j+1), cells: bracketRows(spans, len(sent), RolePredicate), labels: true})
+			}
+		}
+		writePrettyTable(bw, cols, len(sent), color)
+	}
+	return bw.Flush()
+}
+
+type prettyColumn struct {
+	header string
+	cells  []string
+	style  string // 彩色模式下整列的样式
+	labels bool   // 彩色模式下按标签着色, 标签在 ► 之后
+}
+
+func writePrettyTable(bw *bufio.Writer, cols []prettyColumn, n int, color bool) {
+	widths := make([]int, len(cols))
+	for k, c := range cols {
+		widths[k] = displayWidth(c.header)
+		for _, s := range c.cells {
+			if w := displayWidth(s); w > widths[k] {
+				widths[k] = w
+			}
+		}
+	}
+
+	row := func(cell func(k int) (string, string)) {
+		var sb strings.Builder
+		for k := range cols {
+			s, style := cell(k)
+			if k > 0 {
+				sb.WriteByte(' ')
+			}
+			pad := strings.Repeat(" ", widths[k]-displayWidth(s))
+			if color && style != "" && s != "" {
+				s = style + s + ansiReset
+			}
+			sb.WriteString(s)
+			if k < len(cols)-1 {
+				sb.WriteString(pad)
+			}
+		}
+		bw.WriteString(strings.TrimRight(sb.String(), " "))
+		bw.WriteString("\n")
+	}
+
+	row(func(k int) (string, string) { return cols[k].header, ansiBold })
+	row(func(k int) (string, string) { return strings.Repeat("─", displayWidth(cols[k].header)), "" })
+	for j := 0; j < n; j++ {
+		row(func(k int) (string, string) {
+			c := cols[k]
+			if j >= len(c.cells) {
+				return "", ""
+			}
+			s := c.cells[j]
+			if c.labels && color {
+				if i := strings.LastIndex(s, "►"); i >= 0 && i+len("►") < len(s) {
+					label := s[i+len("►"):]
+					return s[:i+len("►")] + labelColor(label) + label + ansiReset, ""
+				}
+			}
+			return s, c.style
+		})
+	}
+}
+
+// labelColor 同一标签总是使用同一颜色
+func labelColor(label string) string {
+	h := 0
+	for _, r := range label {
+		h = h*31 + int(r)
+	}
+	if h < 0 {
+		h = -h
+	}
+	return ansiPalette[h%len(ansiPalette)]
+}
+
+type labeledSpan struct {
+	label      string
+	begin, end int
+}
+
+// bracketRows 区间标注的括号列: 单词 ───►X, 多词 ◄─┐ ... │ ... ◄─┴►X. 谓词 pred 用 ╟──►
+func bracketRows(spans []labeledSpan, n int, pred string) []string {
+	rows := make([]string, n)
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].begin != spans[j].begin {
+			return spans[i].begin < spans[j].begin
+		}
+		return spans[i].end > spans[j].end
+	})
+	end := -1
+	for _, s := range spans {
+		if s.begin < end || s.begin < 0 || s.begin >= s.end || s.end > n { // 与 ResolveOverlaps 相同, 保留外层和靠前的
+			continue
+		}
+		end = s.end
+		switch {
+		case s.label == pred && pred != "":
+			for j := s.begin; j < s.end; j++ {
+				rows[j] = "╟──►" + s.label
+			}
+		case s.end-s.begin == 1:
+			rows[s.begin] = "───►" + s.label
+		default:
+			rows[s.begin] = "◄─┐"
+			for j := s.begin + 1; j < s.end-1; j++ {
+				rows[j] = "  │"
+			}
+			rows[s.end-1] = "◄─┴►" + s.label
+		}
+	}
+	return rows
+}
+
+// 单元格四个方向的连线
+const (
+	arcUp = 1 << iota
+	arcDown
+	arcLeft
+	arcRight
+	arcArrow
+)
+
+var arcChars = map[int]rune{
+	arcUp | arcDown:                      '│',
+	arcLeft | arcRight:                   '─',
+	arcLeft:                              '─',
+	arcRight:                             '─',
+	arcDown | arcRight:                   '┌',
+	arcUp | arcRight:                     '└',
+	arcDown | arcLeft:                    '┐',
+	arcUp | arcLeft:                      '┘',
+	arcUp | arcDown | arcRight:           '├',
+	arcUp | arcDown | arcLeft:            '┤',
+	arcDown | arcLeft | arcRight:         '┬',
+	arcUp | arcLeft | arcRight:           '┴',
+	arcUp | arcDown | arcLeft | arcRight: '┼',
+}
+
+// depArcRows 依存弧列, 弧画在词的左侧, 箭头指向依存词. 指向根的弧不画, 没有弧时返回 nil
+func depArcRows(dep []DepTuple) []string {
+	type arc struct{ from, to, lo, hi, lane int }
+	var arcs []arc
+	for i, a := range dep {
+		if a.Head <= 0 || a.Head > len(dep) || a.Head-1 == i {
+			continue
+		}
+		h := a.Head - 1
+		lo, hi := h, i
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		arcs = append(arcs, arc{from: h, to: i, lo: lo, hi: hi})
+	}
+	if len(arcs) == 0 {
+		return nil
+	}
+
+	// 短弧靠内, 同一道内的弧不相交
+	sort.SliceStable(arcs, func(i, j int) bool { return arcs[i].hi-arcs[i].lo < arcs[j].hi-arcs[j].lo })
+	var lanes [][]arc
+	for k := range arcs {
+		a := &arcs[k]
+		for a.lane = 0; a.lane < len(lanes); a.lane++ {
+			free := true
+			for _, b := range lanes[a.lane] {
+				if a.lo <= b.hi && b.lo <= a.hi {
+					free = false
+					break
+				}
+			}
+			if free {
+				break
+			}
+		}
+		if a.lane == len(lanes) {
+			lanes = append(lanes, nil)
+		}
+		lanes[a.lane] = append(lanes[a.lane], *a)
+	}
+
+	width := 2*len(lanes) + 1
+	grid := make([][]int, len(dep))
+	for i := range grid {
+		grid[i] = make([]int, width)
+	}
+	for _, a := range arcs {
+		x := 2 * (len(lanes) - 1 - a.lane)
+		for y := a.lo; y <= a.hi; y++ {
+			if y > a.lo {
+				grid[y][x] |= arcUp
+			}
+			if y < a.hi {
+				grid[y][x] |= arcDown
+			}
+		}
+		for _, y := range []int{a.from, a.to} {
+			grid[y][x] |= arcRight
+			for k := x + 1; k < width; k++ {
+				grid[y][k] |= arcLeft | arcRight
+			}
+		}
+		grid[a.to][width-1] |= arcArrow
+	}
+
+	rows := make([]string, len(dep))
+	for i, line := range grid {
+		var sb strings.Builder
+		for _, m := range line {
+			if m&arcArrow != 0 {
+				sb.WriteRune('►')
+			} else if c, ok := arcChars[m]; ok {
+				sb.WriteRune(c)
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		rows[i] = sb.String()
+	}
+	return rows
+}
+
+// displayWidth 终端显示宽度: 中日韩及全角字符占两列, 组合字符和 ANSI 颜色序列不占列
+func displayWidth(s string) int {
+	w := 0
+	escape := false
+	for _, r := range s {
+		switch {
+		case escape:
+			escape = r != 'm'
+		case r == '\x1b':
+			escape = true
+		case unicode.Is(unicode.Mn, r):
+		case isWide(r):
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
@@ -0,0 +1,36 @@
+package hanlp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrettyPrint(t *testing.T) {
+	resp := newSampleResp(t)
+
+	var buf bytes.Buffer
+	if err := resp.PrettyPrint(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `Dep Tree Tok    Relation PoS NER Type     PA1
+──────── ───    ──────── ─── ────────     ───
+  ┌─►    晓美焰 nsubj    NR  ───►PERSON   ───►ARG0
+┌─┴──    来到   root     VV               ╟──►PRED
+│ ┌─►    北京   nn       NR  ◄─┐          ◄─┐
+└─┴─►    立方庭 dobj     NR  ◄─┴►LOCATION ◄─┴►ARG1
+`
+	if buf.String() != want {
+		t.Errorf("PrettyPrint =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	resp.PrettyPrint(&buf, true)
+	if !strings.Contains(buf.String(), ansiBold+"Tok"+ansiReset) || !strings.Contains(buf.String(), labelColor("PERSON")+"PERSON"+ansiReset) {
+		t.Errorf("PrettyPrint color = %q", buf.String())
+	}
+
+	if w := displayWidth("晓美焰ab"); w != 8 {
+		t.Errorf("displayWidth = %d", w)
+	}
+}
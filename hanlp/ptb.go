@@ -0,0 +1,233 @@
+package hanlp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// HanResp.Con 中每句是一个无标签的包装节点, 有标签节点的子节点同样包在无标签节点中, 叶子(词)直接挂在词性节点下.
+// 以下函数屏蔽这层包装
+
+// conNode 去掉外层包装, 返回有标签的节点
+func conNode(t ConTuple) ConTuple {
+	for t.Key == "" && len(t.Value) == 1 {
+		t = t.Value[0]
+	}
+	return t
+}
+
+// conChildren 有标签节点的子节点(已去掉包装)
+func conChildren(t ConTuple) []ConTuple {
+	var re []ConTuple
+	for _, v := range t.Value {
+		if v.Key == "" {
+			for _, v1 := range v.Value {
+				re = append(re, conNode(v1))
+			}
+			continue
+		}
+		re = append(re, v)
+	}
+	return re
+}
+
+// newConNode 构造与 HanLP 解码结果结构一致的节点
+func newConNode(label string, children []ConTuple) ConTuple {
+	n := ConTuple{Key: label}
+	for _, c := range children {
+		if len(c.Value) == 0 { // 叶子
+			n.Value = append(n.Value, c)
+		} else {
+			n.Value = append(n.Value, ConTuple{Value: []ConTuple{c}})
+		}
+	}
+	return n
+}
+
+var ptbEscaper = strings.NewReplacer("(", "-LRB-", ")", "-RRB-")
+var ptbUnescaper = strings.NewReplacer("-LRB-", "(", "-RRB-", ")")
+
+// ptbToken 括号转义为 -LRB-/-RRB-, 空白替换为下划线
+func ptbToken(s string) string {
+	return ptbEscaper.Replace(strings.Join(strings.Fields(s), "_"))
+}
+
+// PTB Penn Treebank 括号格式, 如 (TOP (IP (NP (NR 晓美焰)) (VP ...)))
+func (t ConTuple) PTB() string {
+	var sb strings.Builder
+	writePTB(&sb, t)
+	return sb.String()
+}
+
+func writePTB(sb *strings.Builder, t ConTuple) {
+	if t.Key == "" { // 包装节点
+		for i, v := range t.Value {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			writePTB(sb, v)
+		}
+		return
+	}
+
+	children := conChildren(t)
+	if len(children) == 0 {
+		sb.WriteString(ptbToken(t.Key))
+		return
+	}
+	sb.WriteByte('(')
+	sb.WriteString(ptbToken(t.Key))
+	for _, c := range children {
+		sb.WriteByte(' ')
+		writePTB(sb, c)
+	}
+	sb.WriteByte(')')
+}
+
+// PrettyPTB 多行缩进的括号格式, 子节点都是词性节点时写在一行
+func (t ConTuple) PrettyPTB(indent string) string {
+	var sb strings.Builder
+	t = conNode(t)
+	if t.Key == "" {
+		for i, v := range t.Value {
+			if i > 0 {
+				sb.WriteByte('\n')
+			}
+			writePrettyPTB(&sb, conNode(v), indent, 0)
+		}
+	} else {
+		writePrettyPTB(&sb, t, indent, 0)
+	}
+	return sb.String()
+}
+
+func writePrettyPTB(sb *strings.Builder, t ConTuple, indent string, depth int) {
+	children := conChildren(t)
+	flat := true
+	for _, c := range children {
+		if !conIsPreterminal(c) && len(conChildren(c)) > 0 {
+			flat = false
+			break
+		}
+	}
+	if flat {
+		writePTB(sb, t)
+		return
+	}
+
+	sb.WriteByte('(')
+	sb.WriteString(ptbToken(t.Key))
+	for _, c := range children {
+		sb.WriteByte('\n')
+		sb.WriteString(strings.Repeat(indent, depth+1))
+		writePrettyPTB(sb, c, indent, depth+1)
+	}
+	sb.WriteByte(')')
+}
+
+// conIsPreterminal 词性节点: 子节点全部是叶子
+func conIsPreterminal(t ConTuple) bool {
+	children := conChildren(t)
+	if len(children) == 0 {
+		return false
+	}
+	for _, c := range children {
+		if len(conChildren(c)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ParsePTB 解析一棵 Penn Treebank 括号格式的树, 返回与 HanResp.Con 元素相同的包装节点.
+// 支持无标签的最外层括号 ( (S ...) )
+func ParsePTB(s string) (ConTuple, error) {
+	p := &ptbParser{src: []rune(s)}
+	if p.next() != "(" {
+		return ConTuple{}, p.errorf("want '('")
+	}
+	t, err := p.tree()
+	if err != nil {
+		return ConTuple{}, err
+	}
+	if tok := p.next(); tok != "" {
+		return ConTuple{}, p.errorf("unexpected %q after tree", tok)
+	}
+
+	if t.Key == "" { // 无标签的最外层括号
+		return t, nil
+	}
+	return ConTuple{Value: []ConTuple{t}}, nil
+}
+
+type ptbParser struct {
+	src []rune
+	pos int
+}
+
+// next 返回 "(", ")", 符号, 或结束时返回 ""
+func (p *ptbParser) next() string {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return ""
+	}
+	if r := p.src[p.pos]; r == '(' || r == ')' {
+		p.pos++
+		return string(r)
+	}
+	begin := p.pos
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[begin:p.pos])
+}
+
+// tree 已读入 "(", 读到对应的 ")"
+func (p *ptbParser) tree() (ConTuple, error) {
+	label := ""
+	var children []ConTuple
+	for first := true; ; first = false {
+		tok := p.next()
+		switch tok {
+		case "":
+			return ConTuple{}, p.errorf("unexpected end, want ')'")
+		case ")":
+			if label == "" {
+				if len(children) == 0 {
+					return ConTuple{}, p.errorf("empty tree")
+				}
+				return ConTuple{Value: children}, nil
+			}
+			if len(children) == 0 {
+				return ConTuple{}, p.errorf("node %q has no children", label)
+			}
+			return newConNode(label, children), nil
+		case "(":
+			c, err := p.tree()
+			if err != nil {
+				return ConTuple{}, err
+			}
+			if c.Key == "" {
+				return ConTuple{}, p.errorf("unlabeled subtree")
+			}
+			children = append(children, c)
+		default:
+			if first {
+				label = ptbUnescaper.Replace(tok)
+			} else {
+				children = append(children, ConTuple{Key: ptbUnescaper.Replace(tok)})
+			}
+		}
+	}
+}
+
+func (p *ptbParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("ptb: at %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,38 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPTB(t *testing.T) {
+	resp := newSampleResp(t)
+
+	want := "(TOP (IP (NP (NR 晓美焰)) (VP (VV 来到) (NP (NR 北京) (NR 立方庭)))))"
+	if got := resp.Con[0].PTB(); got != want {
+		t.Errorf("PTB = %s, want %s", got, want)
+	}
+
+	tree, err := ParsePTB(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tree, resp.Con[0]) {
+		t.Errorf("ParsePTB = %+v, want %+v", tree, resp.Con[0])
+	}
+
+	pretty := "(TOP\n  (IP\n    (NP (NR 晓美焰))\n    (VP\n      (VV 来到)\n      (NP (NR 北京) (NR 立方庭)))))"
+	if got := tree.PrettyPTB("  "); got != pretty {
+		t.Errorf("PrettyPTB =\n%s\nwant\n%s", got, pretty)
+	}
+	if tree, err = ParsePTB(pretty); err != nil || tree.PTB() != want {
+		t.Errorf("ParsePTB(pretty) = %s, %v", tree.PTB(), err)
+	}
+
+	if tree, err = ParsePTB("( (S (NN -LRB-)) )"); err != nil || tree.PTB() != "(S (NN -LRB-))" || conNode(tree).Value[0].Value[0].Value[0].Key != "(" {
+		t.Errorf("ParsePTB unlabeled root = %+v, %v", tree, err)
+	}
+	if _, err = ParsePTB("(TOP (NP x)"); err == nil {
+		t.Errorf("want error for unbalanced brackets")
+	}
+}
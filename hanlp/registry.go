@@ -0,0 +1,94 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// TaskDecoder 将服务端某个任务键的原始 json 解码为自定义结构
+type TaskDecoder func(json.RawMessage) (interface{}, error)
+
+var (
+	taskDecodersMu sync.RWMutex
+	taskDecoders   = make(map[string]TaskDecoder)
+)
+
+// RegisterTaskDecoder 注册任务键(如 "my/task")的解码器, 结果通过 HanResp.Task(name) 获取. fn 为 nil 时取消注册
+func RegisterTaskDecoder(name string, fn TaskDecoder) {
+	taskDecodersMu.Lock()
+	defer taskDecodersMu.Unlock()
+
+	if fn == nil {
+		delete(taskDecoders, name)
+		return
+	}
+	taskDecoders[name] = fn
+}
+
+func getTaskDecoder(name string) (TaskDecoder, bool) {
+	taskDecodersMu.RLock()
+	defer taskDecodersMu.RUnlock()
+
+	fn, ok := taskDecoders[name]
+	return fn, ok
+}
+
+// Task 任务结果: 已注册解码器的结果优先, 其次为内置任务的类型化字段
+func (r *HanResp) Task(name string) (interface{}, bool) {
+	if v, ok := r.tasks[name]; ok {
+		return v, true
+	}
+
+	v, n, known := r.builtin(name)
+	if !known {
+		return nil, false
+	}
+	_, ok := r.raw[name]
+	return v, ok || n > 0
+}
+
+// builtinTasks 内置任务键, 与 HanResp 的类型化字段一一对应
+var builtinTasks = []string{
+	"tok/fine", "tok/coarse", "pos/ctb", "pos/pku", "pos/863",
+	"ner/pku", "ner/msra", "ner/ontonotes", "srl", "dep",
+	"sdp", "sdp/dm", "sdp/pas", "sdp/psd", "con",
+	"tok", "lem", "fea", "pos", "xpos", "ner",
+}
+
+// builtin 内置任务的类型化字段及其句子数
+func (r *HanResp) builtin(name string) (v interface{}, n int, known bool) {
+	if p := r.column(name); p != nil {
+		return *p, len(*p), true
+	} else if p := r.nerColumn(name); p != nil {
+		return *p, len(*p), true
+	} else if p := r.sdpColumn(name); p != nil {
+		return *p, len(*p), true
+	}
+
+	switch name {
+	case "srl":
+		return r.Srl, len(r.Srl), true
+	case "dep":
+		return r.Dep, len(r.Dep), true
+	case "con":
+		return r.Con, len(r.Con), true
+	}
+	return nil, 0, false
+}
+
+// Raw 任务键的原始 json
+func (r *HanResp) Raw(name string) (json.RawMessage, bool) {
+	v, ok := r.raw[name]
+	return v, ok
+}
+
+// TaskNames 服务端返回的所有任务键
+func (r *HanResp) TaskNames() []string {
+	re := make([]string, 0, len(r.raw))
+	for k := range r.raw {
+		re = append(re, k)
+	}
+	sort.Strings(re)
+	return re
+}
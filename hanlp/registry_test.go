@@ -0,0 +1,46 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterTaskDecoder(t *testing.T) {
+	RegisterTaskDecoder("my/task", func(b json.RawMessage) (interface{}, error) {
+		var v []int
+		err := json.Unmarshal(b, &v)
+		return v, err
+	})
+	defer RegisterTaskDecoder("my/task", nil)
+
+	resp, err := UnmarshalHanResp([]byte(`{"tok/fine": [["a"]], "my/task": [1, 2], "other": {"k": 1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := resp.Task("my/task"); !ok || !reflect.DeepEqual(v, []int{1, 2}) {
+		t.Errorf("Task(my/task) = %v, %v", v, ok)
+	}
+	if v, ok := resp.Raw("other"); !ok || string(v) != `{"k": 1}` {
+		t.Errorf("Raw(other) = %s, %v", v, ok)
+	}
+	if v, ok := resp.Task("tok/fine"); !ok || !reflect.DeepEqual(v, [][]string{{"a"}}) {
+		t.Errorf("Task(tok/fine) = %v, %v", v, ok)
+	}
+	if got := resp.TaskNames(); !reflect.DeepEqual(got, []string{"my/task", "other", "tok/fine"}) {
+		t.Errorf("TaskNames = %v", got)
+	}
+
+	// 注销后未知任务只保留原始 JSON
+	RegisterTaskDecoder("my/task", nil)
+	resp, err = UnmarshalHanResp([]byte(`{"my/task": [1, 2]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := resp.Task("my/task"); ok {
+		t.Errorf("Task(my/task) after unregister = %v", v)
+	}
+	if v, ok := resp.Raw("my/task"); !ok || string(v) != `[1, 2]` {
+		t.Errorf("Raw(my/task) = %s, %v", v, ok)
+	}
+}
@@ -0,0 +1,241 @@
+package hanlp
+
+import (
+	"fmt"
+)
+
+// SemanticEdge 语义依存弧, From 为父节点, 下标从 0 开始
+type SemanticEdge struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Label string `json:"label"`
+}
+
+// SemanticGraph 一句话的语义依存图. 与依存树不同, 每个词可以有多个父节点, 也可以没有父节点(如标点).
+// HanResp.Sdp 的 Head 从 1 开始、0 表示根, 指向根的弧不作为边, 其子节点记为 Tops
+type SemanticGraph struct {
+	Tokens []string // 可为空
+	Edges  []SemanticEdge
+	tops   []int
+	in     [][]int // Edges 下标
+	out    [][]int
+}
+
+// NewSemanticGraph 由一句的 sdp 结果构造, tokens 可为 nil
+func NewSemanticGraph(arcs [][]DepTuple, tokens []string) (*SemanticGraph, error) {
+	if tokens != nil && len(tokens) != len(arcs) {
+		return nil, fmt.Errorf("sdp: %d tokens but %d nodes", len(tokens), len(arcs))
+	}
+
+	g := &SemanticGraph{Tokens: tokens, in: make([][]int, len(arcs)), out: make([][]int, len(arcs))}
+	for i, heads := range arcs {
+		for _, a := range heads {
+			if a.Head < 0 || a.Head > len(arcs) {
+				return nil, fmt.Errorf("sdp: token %d head %d out of range", i+1, a.Head)
+			}
+			if a.Head == 0 {
+				if !containsInt(g.tops, i) {
+					g.tops = append(g.tops, i)
+				}
+				continue
+			}
+			g.in[i] = append(g.in[i], len(g.Edges))
+			g.out[a.Head-1] = append(g.out[a.Head-1], len(g.Edges))
+			g.Edges = append(g.Edges, SemanticEdge{From: a.Head - 1, To: i, Label: a.Relation})
+		}
+	}
+	return g, nil
+}
+
+// SemanticGraphs 每句一个语义依存图, scheme 为 sdp, sdp/dm, sdp/pas, sdp/psd 之一, 为空时取第一个有结果的
+func (r *HanResp) SemanticGraphs(scheme string) ([]*SemanticGraph, error) {
+	if scheme == "" {
+		if schemes := r.SdpSchemes(); len(schemes) > 0 {
+			scheme = schemes[0]
+		} else {
+			return nil, nil
+		}
+	}
+	p := r.sdpColumn(scheme)
+	if p == nil {
+		return nil, fmt.Errorf("sdp: unknown scheme %q", scheme)
+	}
+
+	toks := r.Tokens()
+	re := make([]*SemanticGraph, len(*p))
+	for i, arcs := range *p {
+		var sent []string
+		if i < len(toks) {
+			sent = toks[i]
+		}
+		g, err := NewSemanticGraph(arcs, sent)
+		if err != nil {
+			return nil, fmt.Errorf("%s: sentence %d: %v", scheme, i, err)
+		}
+		re[i] = g
+	}
+	return re, nil
+}
+
+// Len 节点(词)数
+func (g *SemanticGraph) Len() int {
+	return len(g.in)
+}
+
+// Tops 挂在根上的节点
+func (g *SemanticGraph) Tops() []int {
+	return g.tops
+}
+
+// InEdges 指向 i 的边
+func (g *SemanticGraph) InEdges(i int) []SemanticEdge {
+	return g.edges(g.in[i])
+}
+
+// OutEdges 从 i 出发的边
+func (g *SemanticGraph) OutEdges(i int) []SemanticEdge {
+	return g.edges(g.out[i])
+}
+
+func (g *SemanticGraph) edges(idx []int) []SemanticEdge {
+	re := make([]SemanticEdge, len(idx))
+	for k, e := range idx {
+		re[k] = g.Edges[e]
+	}
+	return re
+}
+
+// Heads i 的全部父节点
+func (g *SemanticGraph) Heads(i int) []int {
+	var re []int
+	for _, e := range g.in[i] {
+		re = append(re, g.Edges[e].From)
+	}
+	return re
+}
+
+// Dependents i 的全部子节点
+func (g *SemanticGraph) Dependents(i int) []int {
+	var re []int
+	for _, e := range g.out[i] {
+		re = append(re, g.Edges[e].To)
+	}
+	return re
+}
+
+// Edge from 到 to 的边的关系, 不存在时 ok 为 false
+func (g *SemanticGraph) Edge(from, to int) (label string, ok bool) {
+	for _, e := range g.out[from] {
+		if g.Edges[e].To == to {
+			return g.Edges[e].Label, true
+		}
+	}
+	return "", false
+}
+
+// IsReentrant i 有多个父节点(根也计入)
+func (g *SemanticGraph) IsReentrant(i int) bool {
+	n := len(g.in[i])
+	if containsInt(g.tops, i) {
+		n++
+	}
+	return n > 1
+}
+
+// Reentrancies 全部有多个父节点的节点
+func (g *SemanticGraph) Reentrancies() []int {
+	var re []int
+	for i := range g.in {
+		if g.IsReentrant(i) {
+			re = append(re, i)
+		}
+	}
+	return re
+}
+
+// Components 忽略方向的连通分量, 每个分量按词序, 分量按首个词排序. 没有边的词单独成为一个分量
+func (g *SemanticGraph) Components() [][]int {
+	comp := make([]int, g.Len())
+	for i := range comp {
+		comp[i] = -1
+	}
+
+	var re [][]int
+	for i := range comp {
+		if comp[i] >= 0 {
+			continue
+		}
+		id := len(re)
+		comp[i] = id
+		queue := []int{i}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			for _, m := range g.neighbors(n, false) {
+				if comp[m] < 0 {
+					comp[m] = id
+					queue = append(queue, m)
+				}
+			}
+		}
+		var nodes []int
+		for j := i; j < len(comp); j++ {
+			if comp[j] == id {
+				nodes = append(nodes, j)
+			}
+		}
+		re = append(re, nodes)
+	}
+	return re
+}
+
+// Path 忽略方向时 from 到 to 的最短路径(含两端), 不连通时返回 nil
+func (g *SemanticGraph) Path(from, to int) []int {
+	return g.shortestPath(from, to, false)
+}
+
+// DirectedPath 沿父节点到子节点方向 from 到 to 的最短路径(含两端), 不可达时返回 nil
+func (g *SemanticGraph) DirectedPath(from, to int) []int {
+	return g.shortestPath(from, to, true)
+}
+
+func (g *SemanticGraph) shortestPath(from, to int, directed bool) []int {
+	prev := make([]int, g.Len())
+	for i := range prev {
+		prev[i] = -1
+	}
+	prev[from] = from
+	queue := []int{from}
+	for len(queue) > 0 && prev[to] < 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, m := range g.neighbors(n, directed) {
+			if prev[m] < 0 {
+				prev[m] = n
+				queue = append(queue, m)
+			}
+		}
+	}
+	if prev[to] < 0 {
+		return nil
+	}
+
+	var re []int
+	for n := to; n != from; n = prev[n] {
+		re = append(re, n)
+	}
+	re = append(re, from)
+	for i, j := 0, len(re)-1; i < j; i, j = i+1, j-1 {
+		re[i], re[j] = re[j], re[i]
+	}
+	return re
+}
+
+// neighbors 子节点, 不考虑方向时也包括父节点
+func (g *SemanticGraph) neighbors(i int, directed bool) []int {
+	re := g.Dependents(i)
+	if !directed {
+		re = append(re, g.Heads(i)...)
+	}
+	return re
+}
@@ -0,0 +1,47 @@
+package hanlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSemanticGraph(t *testing.T) {
+	// 他 喜欢 吃 苹果: 他 同时是 喜欢 和 吃 的施事
+	g, err := NewSemanticGraph([][]DepTuple{
+		{{2, "Agt"}, {3, "Agt"}},
+		{{0, "Root"}},
+		{{2, "dCont"}},
+		{{3, "Pat"}},
+		nil,
+	}, []string{"他", "喜欢", "吃", "苹果", "。"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(g.Tops(), []int{1}) || !reflect.DeepEqual(g.Heads(0), []int{1, 2}) {
+		t.Errorf("Tops = %v, Heads(0) = %v", g.Tops(), g.Heads(0))
+	}
+	if got := g.Reentrancies(); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("Reentrancies = %v", got)
+	}
+	if l, ok := g.Edge(2, 3); !ok || l != "Pat" {
+		t.Errorf("Edge(2, 3) = %q, %v", l, ok)
+	}
+	if got := g.Components(); !reflect.DeepEqual(got, [][]int{{0, 1, 2, 3}, {4}}) {
+		t.Errorf("Components = %v", got)
+	}
+	if got := g.Path(0, 3); !reflect.DeepEqual(got, []int{0, 2, 3}) {
+		t.Errorf("Path(0, 3) = %v", got)
+	}
+	if g.DirectedPath(0, 3) != nil || !reflect.DeepEqual(g.DirectedPath(1, 3), []int{1, 2, 3}) {
+		t.Errorf("DirectedPath = %v, %v", g.DirectedPath(0, 3), g.DirectedPath(1, 3))
+	}
+
+	resp := newSampleResp(t)
+	if gs, err := resp.SemanticGraphs(""); err != nil || len(gs) != 1 || len(gs[0].Edges) != 3 {
+		t.Errorf("SemanticGraphs = %v, %v", gs, err)
+	}
+	if _, err := resp.SemanticGraphs("sdp/xx"); err == nil {
+		t.Error("unknown scheme want error")
+	}
+}
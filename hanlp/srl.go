@@ -0,0 +1,184 @@
+package hanlp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 常用语义角色, 见 https://hanlp.hankcs.com/docs/annotations/srl/cpb.html
+const (
+	RolePredicate = "PRED"
+	RoleAgent     = "ARG0"
+	RolePatient   = "ARG1"
+	RoleTime      = "ARGM-TMP"
+	RoleLocation  = "ARGM-LOC"
+)
+
+// Span 词区间及其文本, 左闭右开
+type Span struct {
+	Text  string `json:"text"`
+	Begin int    `json:"begin"`
+	End   int    `json:"end"`
+}
+
+// Arg 论元
+type Arg struct {
+	Span
+	Role string `json:"role"`
+}
+
+// Frame 一个谓词及其论元
+type Frame struct {
+	Predicate Span  `json:"predicate"`
+	Args      []Arg `json:"args"`
+}
+
+// Event 谓词论元结构的 "谁在何时何地对谁做了什么" 视图, 缺失的角色为空串
+type Event struct {
+	Who   string `json:"who,omitempty"`
+	What  string `json:"what"`
+	Whom  string `json:"whom,omitempty"`
+	When  string `json:"when,omitempty"`
+	Where string `json:"where,omitempty"`
+}
+
+// NewFrame 由 HanResp.Srl 中一个谓词的结果构造, 标签为 PRED 的是谓词
+func NewFrame(tuples []SrlTuple) (Frame, error) {
+	var f Frame
+	hasPred := false
+	for _, t := range tuples {
+		s := Span{Text: t.ArgPred, Begin: t.Begin, End: t.End}
+		if t.Label == RolePredicate {
+			if hasPred {
+				return Frame{}, fmt.Errorf("srl: more than one predicate: %q and %q", f.Predicate.Text, t.ArgPred)
+			}
+			f.Predicate, hasPred = s, true
+			continue
+		}
+		f.Args = append(f.Args, Arg{Span: s, Role: t.Label})
+	}
+	if !hasPred {
+		return Frame{}, fmt.Errorf("srl: no predicate in %v", tuples)
+	}
+	sort.SliceStable(f.Args, func(i, j int) bool { return f.Args[i].Begin < f.Args[j].Begin })
+	return f, nil
+}
+
+// Frames 每句的谓词论元结构
+func (r *HanResp) Frames() ([][]Frame, error) {
+	re := make([][]Frame, len(r.Srl))
+	for i, sent := range r.Srl {
+		frames, err := newFrames(sent)
+		if err != nil {
+			return nil, fmt.Errorf("srl: sentence %d: %v", i, err)
+		}
+		re[i] = frames
+	}
+	return re, nil
+}
+
+// Frames 句子的谓词论元结构
+func (s *Sentence) Frames() ([]Frame, error) {
+	return newFrames(s.Srl)
+}
+
+func newFrames(sent [][]SrlTuple) ([]Frame, error) {
+	re := make([]Frame, len(sent))
+	for j, tuples := range sent {
+		f, err := NewFrame(tuples)
+		if err != nil {
+			return nil, err
+		}
+		re[j] = f
+	}
+	return re, nil
+}
+
+// Role 指定角色的全部论元, 如 Role("ARG2")
+func (f Frame) Role(role string) []Arg {
+	var re []Arg
+	for _, a := range f.Args {
+		if a.Role == role {
+			re = append(re, a)
+		}
+	}
+	return re
+}
+
+// first 指定角色的第一个论元
+func (f Frame) first(role string) *Arg {
+	for i := range f.Args {
+		if f.Args[i].Role == role {
+			return &f.Args[i]
+		}
+	}
+	return nil
+}
+
+// Agent 施事 ARG0, 没有时返回 nil
+func (f Frame) Agent() *Arg {
+	return f.first(RoleAgent)
+}
+
+// Patient 受事 ARG1, 没有时返回 nil
+func (f Frame) Patient() *Arg {
+	return f.first(RolePatient)
+}
+
+// Time 时间 ARGM-TMP, 没有时返回 nil
+func (f Frame) Time() *Arg {
+	return f.first(RoleTime)
+}
+
+// Location 地点 ARGM-LOC, 没有时返回 nil
+func (f Frame) Location() *Arg {
+	return f.first(RoleLocation)
+}
+
+// Span 谓词和全部论元覆盖的词区间
+func (f Frame) Span() (begin, end int) {
+	begin, end = f.Predicate.Begin, f.Predicate.End
+	for _, a := range f.Args {
+		if a.Begin < begin {
+			begin = a.Begin
+		}
+		if a.End > end {
+			end = a.End
+		}
+	}
+	return begin, end
+}
+
+// Text 由句子的词序列还原谓词论元结构覆盖的文本
+func (f Frame) Text(tokens []string) string {
+	begin, end := f.Span()
+	if begin < 0 || end > len(tokens) || begin > end {
+		return ""
+	}
+	return joinTokens(tokens[begin:end])
+}
+
+// Tuples 转回 HanResp.Srl 的格式, 按词序
+func (f Frame) Tuples() []SrlTuple {
+	re := make([]SrlTuple, 0, len(f.Args)+1)
+	for _, a := range f.Args {
+		re = append(re, SrlTuple{ArgPred: a.Text, Label: a.Role, Begin: a.Begin, End: a.End})
+	}
+	re = append(re, SrlTuple{ArgPred: f.Predicate.Text, Label: RolePredicate, Begin: f.Predicate.Begin, End: f.Predicate.End})
+	sort.SliceStable(re, func(i, j int) bool { return re[i].Begin < re[j].Begin })
+	return re
+}
+
+// Event 谁(ARG0)在何时(ARGM-TMP)何地(ARGM-LOC)对谁(ARG1)做了什么(谓词)
+func (f Frame) Event() Event {
+	e := Event{What: f.Predicate.Text}
+	for _, v := range []struct {
+		p   *string
+		arg *Arg
+	}{{&e.Who, f.Agent()}, {&e.Whom, f.Patient()}, {&e.When, f.Time()}, {&e.Where, f.Location()}} {
+		if v.arg != nil {
+			*v.p = v.arg.Text
+		}
+	}
+	return e
+}
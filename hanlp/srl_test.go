@@ -0,0 +1,29 @@
+package hanlp
+
+import "testing"
+
+func TestFrames(t *testing.T) {
+	resp := newSampleResp(t)
+	frames, err := resp.Frames()
+	if err != nil || len(frames) != 1 || len(frames[0]) != 1 {
+		t.Fatalf("Frames = %v, %v", frames, err)
+	}
+
+	f := frames[0][0]
+	if f.Predicate.Text != "来到" || f.Agent().Text != "晓美焰" || f.Patient().Begin != 2 || f.Time() != nil {
+		t.Errorf("Frame = %+v", f)
+	}
+	if e := f.Event(); e != (Event{Who: "晓美焰", What: "来到", Whom: "北京立方庭"}) {
+		t.Errorf("Event = %+v", e)
+	}
+	if got := f.Text(resp.Tokens()[0]); got != "晓美焰来到北京立方庭" {
+		t.Errorf("Text = %q", got)
+	}
+	if got := f.Tuples(); len(got) != 3 || got[1].Label != RolePredicate || got[2] != resp.Srl[0][0][2] {
+		t.Errorf("Tuples = %v", got)
+	}
+
+	if _, err := NewFrame([]SrlTuple{{"晓美焰", "ARG0", 0, 1}}); err == nil {
+		t.Error("frame without predicate want error")
+	}
+}
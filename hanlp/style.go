@@ -0,0 +1,133 @@
+package hanlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/xxjwxc/public/mylog"
+)
+
+// Style 文本风格转换的目标风格
+type Style string
+
+const (
+	StyleGovDoc       Style = "gov_doc"       // 公文
+	StyleModernPoetry Style = "modern_poetry" // 现代诗
+)
+
+// KnownStyles 客户端已知的风格, 以服务端 /about 公布的为准
+var KnownStyles = []Style{StyleGovDoc, StyleModernPoetry}
+
+// 分批请求的客户端默认值, 并非服务端公布的限制, 可用 WithBatchSize, WithBatchRunes 调整
+const (
+	defaultBatchSize  = 32   // 单次请求的最大句子数
+	defaultBatchRunes = 2048 // 单次请求的最大字符数
+)
+
+// Styles 获取服务端 /about 公布的风格列表
+func (h *hanlp) Styles(opts ...Option) ([]Style, error) {
+	info, err := h.Capabilities(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return info.Styles, nil
+}
+
+// validateStyle 校验目标风格非空. 开启 WithCapabilityCheck 时还要求服务端 /about 公布了该风格
+func (h *hanlp) validateStyle(style Style, opts ...Option) error {
+	if len(style) == 0 {
+		return fmt.Errorf("text_style_transfer: target style is required, e.g. %v", KnownStyles)
+	}
+
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+	if !options.CheckCapabilities {
+		return nil
+	}
+
+	info, err := h.Capabilities(opts...)
+	if err != nil {
+		return err
+	}
+	if !info.SupportsStyle(style) {
+		return fmt.Errorf("text_style_transfer: style %q not supported by server, available: %v", style, info.Styles)
+	}
+	return nil
+}
+
+// TextStyleTransferObj 文本风格转换, 返回与输入一一对应的结果
+func (h *hanlp) TextStyleTransferObj(text []string, style Style, opts ...Option) ([]string, error) {
+	if err := h.validateStyle(style, opts...); err != nil {
+		return nil, err
+	}
+	return h.textStyleTransfer(text, style, opts...)
+}
+
+// TextStyleTransferBatch 文本风格转换, 按请求大小限制分批发送后合并结果
+func (h *hanlp) TextStyleTransferBatch(text []string, style Style, opts ...Option) ([]string, error) {
+	if err := h.validateStyle(style, opts...); err != nil {
+		return nil, err
+	}
+
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	re := make([]string, 0, len(text))
+	for _, batch := range splitBatches(text, options.BatchSize, options.BatchRunes) {
+		tmp, err := h.textStyleTransfer(batch, style, opts...)
+		if err != nil {
+			return nil, err
+		}
+		re = append(re, tmp...)
+	}
+	return re, nil
+}
+
+func (h *hanlp) textStyleTransfer(text []string, style Style, opts ...Option) ([]string, error) {
+	b, err := h.postTextStyleTransfer(text, style, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var re []string
+	if err = json.Unmarshal([]byte(b), &re); err != nil {
+		mylog.Error(err)
+		return nil, err
+	}
+	if len(re) != len(text) {
+		return nil, fmt.Errorf("text_style_transfer: got %d results for %d inputs", len(re), len(text))
+	}
+	return re, nil
+}
+
+// splitBatches 按句子数和字符数切分批次, 超长的单句独占一批. size, runes <= 0 时取默认值
+func splitBatches(text []string, size, runes int) [][]string {
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	if runes <= 0 {
+		runes = defaultBatchRunes
+	}
+
+	var re [][]string
+	var cur []string
+	n := 0
+	for _, s := range text {
+		l := utf8.RuneCountInString(s)
+		if len(cur) > 0 && (len(cur) >= size || n+l > runes) {
+			re = append(re, cur)
+			cur, n = nil, 0
+		}
+		cur = append(cur, s)
+		n += l
+	}
+	if len(cur) > 0 {
+		re = append(re, cur)
+	}
+	return re
+}
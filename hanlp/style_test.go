@@ -0,0 +1,62 @@
+package hanlp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitBatches(t *testing.T) {
+	long := strings.Repeat("长", 10)
+	cases := []struct {
+		text        []string
+		size, runes int
+		want        [][]string
+	}{
+		{nil, 2, 100, nil},
+		{[]string{"a", "b", "c"}, 2, 100, [][]string{{"a", "b"}, {"c"}}},
+		{[]string{"北京", "上海", "广州"}, 10, 4, [][]string{{"北京", "上海"}, {"广州"}}},
+		{[]string{"a", long, "b"}, 10, 5, [][]string{{"a"}, {long}, {"b"}}}, // 超长的单句独占一批
+		{[]string{"a", "b"}, 0, 100, [][]string{{"a", "b"}}},                // size <= 0 时取默认值
+		{[]string{"a", long}, 10, 0, [][]string{{"a", long}}},               // runes <= 0 时取默认值
+	}
+	for _, c := range cases {
+		if got := splitBatches(c.text, c.size, c.runes); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("splitBatches(%v, %d, %d) = %v, want %v", c.text, c.size, c.runes, got, c.want)
+		}
+	}
+}
+
+func TestEmptyStyle(t *testing.T) {
+	h := HanLPClient(WithURL("http://127.0.0.1:0"))
+	if _, err := h.TextStyleTransfer([]string{"a"}, ""); err == nil {
+		t.Error("TextStyleTransfer with empty style want error")
+	}
+	if _, err := h.TextStyleTransferBatch([]string{"a"}, ""); err == nil {
+		t.Error("TextStyleTransferBatch with empty style want error")
+	}
+	// 未开启 WithCapabilityCheck 时不请求 /about
+	if err := h.validateStyle(StyleGovDoc); err != nil {
+		t.Errorf("validateStyle = %v", err)
+	}
+	if err := h.validateStyle(StyleGovDoc, WithCapabilityCheck()); err == nil {
+		t.Error("validateStyle with unreachable /about want error")
+	}
+}
+
+func TestTextStyleTransferCapabilityCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/about" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"text_style_transfer": ["modern_poetry"]}`))
+	}))
+	defer srv.Close()
+
+	h := HanLPClient(WithURL(srv.URL), WithCapabilityCheck())
+	if _, err := h.TextStyleTransfer([]string{"a"}, StyleGovDoc); err == nil {
+		t.Error("TextStyleTransfer with unsupported style want error")
+	}
+}
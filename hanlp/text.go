@@ -0,0 +1,37 @@
+package hanlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// joinTokens 还原句子文本: 中日韩文字之间不加空格, 其它词之间以空格分隔
+func joinTokens(tokens []string) string {
+	var sb strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && needSpace(tokens[i-1], tok) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(tok)
+	}
+	return sb.String()
+}
+
+func needSpace(prev, next string) bool {
+	if prev == "" || next == "" {
+		return false
+	}
+	a, _ := utf8.DecodeLastRuneInString(prev)
+	b, _ := utf8.DecodeRuneInString(next)
+	if isWide(a) || isWide(b) {
+		return false
+	}
+	return !strings.ContainsRune(".,;:!?)]}%'\"", b) && !strings.ContainsRune("([{$", a)
+}
+
+// isWide 中日韩文字及全角字符
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
@@ -0,0 +1,96 @@
+package hanlp
+
+import (
+	"context"
+)
+
+// TokenOffset 词及其在原文中的字符(rune)区间和字节区间, 左闭右开
+type TokenOffset struct {
+	Text      string `json:"text"`
+	Begin     int    `json:"begin"`
+	End       int    `json:"end"`
+	ByteBegin int    `json:"byte_begin"`
+	ByteEnd   int    `json:"byte_end"`
+}
+
+/*
+分词
+Split a document into sentences and tokenize them. Note that it is always faster to tokenize a whole document than
+
+	to tokenize each sentence one by one. So avoid calling this method sentence by sentence but put sentences into
+	a ``list`` and pass them to the ``text`` argument.
+
+	Args:
+	    text: A document (``str``), or a list of sentences (``List[str]``).
+	    coarse: Whether to perform coarse-grained or fine-grained tokenization.
+	    language: The language of input text. ``None`` to use the default language.
+
+	Returns:
+	    A list of tokenized sentences.
+
+	Examples::
+
+	    # Avoid tokenizing sentence by sentence, it is expensive:
+	    HanLP.tokenize('商品和服务。')
+	    [['商品', '和', '服务', '。']]
+	    HanLP.tokenize('阿婆主来到北京立方庭参观自然语义科技公司')
+	    [['阿婆主', '来到', '北京', '立方庭', '参观', '自然', '语义', '科技', '公司']]
+
+	    # Instead, the following codes are much faster:
+	    HanLP.tokenize('商品和服务。阿婆主来到北京立方庭参观自然语义科技公司')
+	    [['商品', '和', '服务', '。'],
+	     ['阿婆主', '来到', '北京', '立方庭', '参观', '自然', '语义', '科技', '公司']]
+
+	    # To tokenize with coarse-grained standard:
+	    HanLP.tokenize('商品和服务。阿婆主来到北京立方庭参观自然语义科技公司', coarse=True)
+	    [['商品', '和', '服务', '。'],
+	     ['阿婆主', '来到', '北京', '立方庭', '参观', '自然语义科技公司']]
+*/
+func (h *hanlp) Tokenize(ctx context.Context, text []string, coarse bool, opts ...Option) ([][]string, error) {
+	options := h.opts
+	for _, f := range opts { // option
+		f(&options)
+	}
+
+	req := &HanReq{
+		Text:     text,
+		Language: options.Language, // (zh,mnt)
+		Tasks:    []string{"tok/fine"},
+	}
+	if coarse {
+		req.Tasks = []string{"tok/coarse"}
+		req.SkipTasks = []string{"tok/fine"}
+	}
+
+	b, err := h.postURL(ctx, options.URL+"/parse", req, getHeader(options))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := UnmarshalHanResp([]byte(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if coarse {
+		return resp.TokCoarse, nil
+	}
+	return resp.TokFine, nil
+}
+
+// TokenizeOffsets 分词并返回每个词在对应输入句子中的字符区间
+func (h *hanlp) TokenizeOffsets(ctx context.Context, text []string, coarse bool, opts ...Option) ([][]TokenOffset, error) {
+	toks, err := h.Tokenize(ctx, text, coarse, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	re := make([][]TokenOffset, len(toks))
+	for i, v := range toks {
+		sent := ""
+		if i < len(text) {
+			sent = text[i]
+		}
+		re[i] = NewAligner(sent).Align(v)
+	}
+	return re, nil
+}
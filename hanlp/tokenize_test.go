@@ -0,0 +1,41 @@
+package hanlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenizeOffsets(t *testing.T) {
+	// 服务端返回的分词: 全角数字被归一化为半角, "〇" 被改写为 "零"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req HanReq
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/parse" || len(req.Tasks) != 1 || req.Tasks[0] != "tok/fine" {
+			t.Errorf("request %s %+v", r.URL.Path, req)
+		}
+		w.Write([]byte(`{"tok/fine": [["商品", "和", "服务", "。"], ["HanLP", "2.0", "发布"], ["二", "零", "二", "一"]]}`))
+	}))
+	defer srv.Close()
+
+	text := []string{"商品和服务。", "HanLP ２．０ 发布", "二〇二一"}
+	got, err := HanLPClient(WithURL(srv.URL)).TokenizeOffsets(context.Background(), text, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]TokenOffset{
+		{{"商品", 0, 2, 0, 6}, {"和", 2, 3, 6, 9}, {"服务", 3, 5, 9, 15}, {"。", 5, 6, 15, 18}},
+		{{"HanLP", 0, 5, 0, 5}, {"2.0", 6, 9, 6, 15}, {"发布", 10, 12, 16, 22}},
+		{{"二", 0, 1, 0, 3}, {"零", 1, 2, 3, 6}, {"二", 2, 3, 6, 9}, {"一", 3, 4, 9, 12}},
+	}
+	for i := range want {
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("offset[%d][%d] = %+v, want %+v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
@@ -58,9 +58,9 @@ func TestMain_test(t *testing.T) {
 	//     此外，在细分的小品种里，建议关注两条主线，一是新能源，比如锂、钴、镍、稀土，二是专精特新主线。（央视财经）`)
 	// fmt.Println(asRes)
 
-	// tstRes, _ := client.TextStyleTransfer([]string{"要以创新驱动高质量发展", "我看到了窗户外面有白色的云和绿色的森林", "国家对中石油寄予厚望"}, "modern_poetry")
+	// tstRes, _ := client.TextStyleTransfer([]string{"要以创新驱动高质量发展", "我看到了窗户外面有白色的云和绿色的森林", "国家对中石油寄予厚望"}, hanlp.StyleModernPoetry)
 	// fmt.Println(tstRes)
-	govRes, _ := client.TextStyleTransfer([]string{"要以创新驱动高质量发展", "我看到了窗户外面有白色的云和绿色的森林", "国家对中石油寄予厚望"}, "gov_doc")
+	govRes, _ := client.TextStyleTransfer([]string{"要以创新驱动高质量发展", "我看到了窗户外面有白色的云和绿色的森林", "国家对中石油寄予厚望"}, hanlp.StyleGovDoc)
 	fmt.Println(govRes)
 
 	// ab, _ := client.About()